@@ -27,6 +27,15 @@ package ugo
 type ChainWrapper struct {
 	Mid Seq    // Mid is for middleware calculations
 	Res Object // Res if for resulting data
+
+	immutable bool // immutable is set when the passed Seq must never be mutated
+}
+
+// Immutable guarantees that no further chaining call mutates the Seq passed to Chain,
+// methods, which change slice in place, work with its copy instead
+func (wrapper *ChainWrapper) Immutable() *ChainWrapper {
+	wrapper.immutable = true
+	return wrapper
 }
 
 // Each is a chaining wrapper for #Each
@@ -173,6 +182,20 @@ func (wrapper *ChainWrapper) LastIndexOf(target Object, cb Comparator) *ChainWra
 	return wrapper
 }
 
+// LastIndexOfSorted is a chaining wrapper for #LastIndexOfSorted
+func (wrapper *ChainWrapper) LastIndexOfSorted(target Object, cb Comparator) *ChainWrapper {
+	wrapper.Res = LastIndexOfSorted(wrapper.Mid, target, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
+// SortedIndex is a chaining wrapper for #SortedIndex
+func (wrapper *ChainWrapper) SortedIndex(target Object, cb Comparator) *ChainWrapper {
+	wrapper.Res = SortedIndex(wrapper.Mid, target, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
 // Contains is a chaining wrapper for #Contains
 func (wrapper *ChainWrapper) Contains(target Object, isSorted bool, cb Comparator) *ChainWrapper {
 	wrapper.Res = Contains(wrapper.Mid, target, isSorted, cb)
@@ -239,7 +262,36 @@ func (wrapper *ChainWrapper) Union(other Seq, cb Comparator) *ChainWrapper {
 
 // SortBy is a chaining wrapper for #SortBy
 func (wrapper *ChainWrapper) SortBy(cb Comparator) *ChainWrapper {
-	wrapper.Mid = SortBy(wrapper.Mid, cb)
+	if wrapper.immutable {
+		wrapper.Mid = SortedCopy(wrapper.Mid, cb)
+	} else {
+		wrapper.Mid = SortBy(wrapper.Mid, cb)
+	}
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// SortWith is a chaining wrapper for #SortWith
+func (wrapper *ChainWrapper) SortWith(algo Sorter, cb Comparator) *ChainWrapper {
+	if wrapper.immutable {
+		wrapper.Mid = SortWith(createCopy(wrapper.Mid), algo, cb)
+	} else {
+		wrapper.Mid = SortWith(wrapper.Mid, algo, cb)
+	}
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// TopK is a chaining wrapper for #TopK
+func (wrapper *ChainWrapper) TopK(k int, cb Comparator) *ChainWrapper {
+	wrapper.Mid = TopK(wrapper.Mid, k, cb)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// BottomK is a chaining wrapper for #BottomK
+func (wrapper *ChainWrapper) BottomK(k int, cb Comparator) *ChainWrapper {
+	wrapper.Mid = BottomK(wrapper.Mid, k, cb)
 	wrapper.Res = wrapper.Mid
 	return wrapper
 }
@@ -267,14 +319,22 @@ func (wrapper *ChainWrapper) Remove(pos int) *ChainWrapper {
 
 // Insert is a chaining wrapper for #Insert
 func (wrapper *ChainWrapper) Insert(tg Object, pos int) *ChainWrapper {
-	wrapper.Mid = Insert(wrapper.Mid, tg, pos)
+	if wrapper.immutable {
+		wrapper.Mid = InsertCopy(wrapper.Mid, tg, pos)
+	} else {
+		wrapper.Mid = Insert(wrapper.Mid, tg, pos)
+	}
 	wrapper.Res = wrapper.Mid
 	return wrapper
 }
 
 // Concat is a chaining wrapper for #Concat
 func (wrapper *ChainWrapper) Concat(next Seq) *ChainWrapper {
-	wrapper.Mid = Concat(wrapper.Mid, next)
+	if wrapper.immutable {
+		wrapper.Mid = ConcatCopy(wrapper.Mid, next)
+	} else {
+		wrapper.Mid = Concat(wrapper.Mid, next)
+	}
 	wrapper.Res = wrapper.Mid
 	return wrapper
 }
@@ -286,6 +346,34 @@ func (wrapper *ChainWrapper) Shuffle() *ChainWrapper {
 	return wrapper
 }
 
+// ShuffleWith is a chaining wrapper for #ShuffleWith
+func (wrapper *ChainWrapper) ShuffleWith(rng Rand) *ChainWrapper {
+	wrapper.Mid = ShuffledCopyWith(wrapper.Mid, rng)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// Sample is a chaining wrapper for #Sample
+func (wrapper *ChainWrapper) Sample(n int, rng Rand) *ChainWrapper {
+	wrapper.Mid = Sample(wrapper.Mid, n, rng)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// SampleWithReplacement is a chaining wrapper for #SampleWithReplacement
+func (wrapper *ChainWrapper) SampleWithReplacement(n int, rng Rand) *ChainWrapper {
+	wrapper.Mid = SampleWithReplacement(wrapper.Mid, n, rng)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// WeightedSample is a chaining wrapper for #WeightedSample
+func (wrapper *ChainWrapper) WeightedSample(cb Callback, n int, rng Rand) *ChainWrapper {
+	wrapper.Mid = WeightedSample(wrapper.Mid, cb, n, rng)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
 // Reverse is a chaining wrapper for #Reverse
 func (wrapper *ChainWrapper) Reverse() *ChainWrapper {
 	wrapper.Mid = ReversedCopy(wrapper.Mid)
@@ -293,6 +381,20 @@ func (wrapper *ChainWrapper) Reverse() *ChainWrapper {
 	return wrapper
 }
 
+// First is a chaining wrapper for #First
+func (wrapper *ChainWrapper) First(n int) *ChainWrapper {
+	wrapper.Mid = First(wrapper.Mid, n)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
+// Last is a chaining wrapper for #Last
+func (wrapper *ChainWrapper) Last(n int) *ChainWrapper {
+	wrapper.Mid = Last(wrapper.Mid, n)
+	wrapper.Res = wrapper.Mid
+	return wrapper
+}
+
 // EqualsStrict is a chaining wrapper for #EqualsStrict
 func (wrapper *ChainWrapper) EqualsStrict(other Seq, cb Comparator) *ChainWrapper {
 	wrapper.Res = EqualsStrict(wrapper.Mid, other, cb)
@@ -307,6 +409,41 @@ func (wrapper *ChainWrapper) EqualsNotStrict(other Seq, cb Comparator) *ChainWra
 	return wrapper
 }
 
+// IsPermutation is a chaining wrapper for #IsPermutation
+func (wrapper *ChainWrapper) IsPermutation(other Seq, cb Comparator) *ChainWrapper {
+	wrapper.Res = IsPermutation(wrapper.Mid, other, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
+// IsSubsequence is a chaining wrapper for #IsSubsequence
+func (wrapper *ChainWrapper) IsSubsequence(sub Seq, cb Comparator) *ChainWrapper {
+	wrapper.Res = IsSubsequence(wrapper.Mid, sub, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
+// IsSuperset is a chaining wrapper for #IsSuperset
+func (wrapper *ChainWrapper) IsSuperset(other Seq, cb Comparator) *ChainWrapper {
+	wrapper.Res = IsSuperset(wrapper.Mid, other, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
+// DeepEqual is a chaining wrapper for #DeepEqual
+func (wrapper *ChainWrapper) DeepEqual(other Object, opts DeepOptions) *ChainWrapper {
+	wrapper.Res = DeepEqual(wrapper.Mid, other, opts)
+	wrapper.Mid = nil
+	return wrapper
+}
+
+// Diff is a chaining wrapper for #Diff
+func (wrapper *ChainWrapper) Diff(other Seq, cb Comparator) *ChainWrapper {
+	wrapper.Res = Diff(wrapper.Mid, other, cb)
+	wrapper.Mid = nil
+	return wrapper
+}
+
 // Value returns result of calculations, you've done through chaining calls
 func (wrapper *ChainWrapper) Value() Object {
 	return wrapper.Res
@@ -334,6 +334,19 @@ func TestChainingSingleValues(t *testing.T) {
 			g.Assert(Chain(nil).LastIndexOf(300, nil).Value()).Equal(-1)
 			g.Assert(Chain(inSeqDif).LastIndexOf(nil, nil).Value()).Equal(-1)
 			g.Assert(Chain(nil).LastIndexOf(nil, nil).Value()).Equal(-1)
+			g.Assert(Chain(inSeq).LastIndexOfSorted(8, comp).Value()).Equal(3)
+			g.Assert(Chain(inSeq).LastIndexOfSorted(9, comp).Value()).Equal(-1)
+		})
+	})
+
+	g.Describe("#SortedIndex()", func() {
+		g.It("Should return the lowest insertion position of target value", func() {
+			inSeq := Seq{-100, 0, 8, 8, 100, 32521}
+
+			g.Assert(Chain(inSeq).SortedIndex(8, comp).Value()).Equal(2)
+			g.Assert(Chain(inSeq).SortedIndex(50, comp).Value()).Equal(4)
+			g.Assert(Chain(inEmpty).SortedIndex(1, comp).Value()).Equal(0)
+			g.Assert(Chain(nil).SortedIndex(1, nil).Value()).Equal(-1)
 		})
 	})
 
@@ -520,6 +533,28 @@ func TestChainingMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#TopK()", func() {
+		g.It("Should return k largest elements", func() {
+			inSeq := Seq{4, 3, 43, 2, 3, -92, 102, 2, 0}
+
+			g.Assert(Chain(inSeq).TopK(3, comp).Value()).Equal(Seq{102, 43, 4})
+			g.Assert(Chain(inSeq).TopK(0, comp).Value()).Equal(inEmpty)
+			g.Assert(Chain(nil).TopK(3, comp).Value()).Equal(inEmpty)
+			g.Assert(Chain(inSeq).TopK(3, nil).Value()).Equal(inEmpty)
+		})
+	})
+
+	g.Describe("#BottomK()", func() {
+		g.It("Should return k least elements", func() {
+			inSeq := Seq{4, 3, 43, 2, 3, -92, 102, 2, 0}
+
+			g.Assert(Chain(inSeq).BottomK(4, comp).Value()).Equal(Seq{-92, 0, 2, 2})
+			g.Assert(Chain(inSingle).BottomK(4, comp).Value()).Equal(inSingle)
+			g.Assert(Chain(nil).BottomK(3, comp).Value()).Equal(inEmpty)
+			g.Assert(Chain(inSeq).BottomK(3, nil).Value()).Equal(inEmpty)
+		})
+	})
+
 	g.Describe("#CountBy()", func() {
 		g.It("Should return map with countings, e. g. keys - callback result, value - number of same results", func() {
 			inSeq := Seq{4, 3, 43, 2, 3, -92, 102, 2, 0}
@@ -615,6 +650,17 @@ func TestChainingMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#First()", func() {
+		g.It("Should return the first n elements of Seq", func() {
+			inSeq := Seq{-1, 0, 11, 1, 11}
+
+			g.Assert(Chain(inSeq).First(2).Value()).Equal(Seq{-1, 0})
+			g.Assert(Chain(inSeq).Last(2).Value()).Equal(Seq{1, 11})
+			g.Assert(Chain(inSeq).SortBy(comp).Last(1).Value()).Equal(Seq{11})
+			g.Assert(Chain(nil).First(2).Value()).Equal(inEmpty)
+		})
+	})
+
 	g.Describe("#Remove()", func() {
 		g.It("Should return Seq without value in given index", func() {
 			inSeq := Seq{3, 34, 23, 333, -12}
@@ -855,4 +901,24 @@ func TestChainingMultipleValues(t *testing.T) {
 			g.Assert(Chain(nil).Without(nil, nil).Value()).Equal(inEmpty)
 		})
 	})
+
+	g.Describe("#Immutable()", func() {
+		g.It("Should never mutate the Seq passed to Chain", func() {
+			inSeq := make(Seq, 5, 8)
+			copy(inSeq, Seq{3, 1, 4, 1, 5})
+			untouched := Seq{3, 1, 4, 1, 5, nil, nil, nil}
+
+			g.Assert(Chain(inSeq).Immutable().SortBy(comp).Value()).Equal(Seq{1, 1, 3, 4, 5})
+			g.Assert(Chain(inSeq).Immutable().Insert(9, 0).Value()).Equal(Seq{9, 3, 1, 4, 1, 5})
+			g.Assert(Chain(inSeq).Immutable().Concat(Seq{9, 2}).Value()).Equal(Seq{3, 1, 4, 1, 5, 9, 2})
+			g.Assert(Chain(inSeq).Immutable().Shuffle().SortBy(comp).Value()).Equal(Seq{1, 1, 3, 4, 5})
+			g.Assert(Chain(inSeq).Immutable().Reverse().Value()).Equal(Seq{5, 1, 4, 1, 3})
+			g.Assert(Chain(inSeq).Immutable().Remove(0).Union(Seq{7}, comp).Value()).Equal(Seq{1, 4, 5, 7})
+			g.Assert(inSeq[:cap(inSeq)]).Equal(untouched)
+		})
+
+		g.It("Should behave as a usual chain for empty Seq", func() {
+			g.Assert(Chain(nil).Immutable().Insert(1, 0).Concat(Seq{2}).SortBy(comp).Value()).Equal(Seq{1, 2})
+		})
+	})
 }
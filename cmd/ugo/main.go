@@ -0,0 +1,470 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command ugo reads JSON array or JSON Lines from stdin and applies the pipeline of ugo operations to it,
+// operations are separated by "|", so the pipeline should be quoted in shell, "|" and spaces inside of
+// quoted values and paths don't split the pipeline:
+//
+//	ugo 'uniq .id | sortby -.score | first 10 | groupby .team' < players.jsonl
+//
+// Fields are addressed by paths like .team, .stats.score or .goals[0] (see ugo.Path), the single dot means the element itself,
+// the leading minus in sortby and top reverses the order. Values are compared by ugo.Compare.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	u "github.com/alxrm/ugo"
+)
+
+const usage = `usage: ugo [-o json|jsonl] 'op [args] | op [args] ...'
+
+operations on sequences:
+  uniq [path]          leaves the first element for each distinct value of path
+  sortby [-]path       sorts stably by path, descending with minus
+  top n [-]path        takes n largest elements by path, smallest with minus
+  first n, last n      takes the first or the last n elements
+  where path value     leaves elements, which value of path equals given JSON value
+  pluck path           replaces elements with their value of path
+  reverse, shuffle     reverses or shuffles elements
+  sample n             takes n random elements
+
+operations, which end the pipeline:
+  groupby path         groups elements by value of path
+  countby path         counts elements by value of path
+  min path, max path   takes the element with the least or the greatest value of path, null if there're none
+  count                counts elements
+`
+
+// operation applies itself to the chain, its arguments are already validated
+type operation struct {
+	name  string
+	ends  bool // ends is set when the operation doesn't return a sequence, so nothing can follow it
+	apply func(chain *u.ChainWrapper) *u.ChainWrapper
+}
+
+func main() {
+	format := flag.String("o", "", "output format: json or jsonl, defaults to the input one")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(os.Stdin, os.Stdout, strings.Join(flag.Args(), " "), *format); err != nil {
+		fmt.Fprintln(os.Stderr, "ugo:", err)
+		os.Exit(1)
+	}
+}
+
+// run reads the input, applies the pipeline and writes the result in given format
+func run(reader io.Reader, writer io.Writer, pipeline, format string) error {
+	if format != "" && format != "json" && format != "jsonl" {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	operations, err := parsePipeline(pipeline)
+	if err != nil {
+		return err
+	}
+
+	seq, isArray, err := readInput(reader)
+	if err != nil {
+		return err
+	}
+
+	chain := u.Chain(seq)
+	for _, op := range operations {
+		chain = op.apply(chain)
+	}
+
+	if format == "" {
+		format = "jsonl"
+		if isArray {
+			format = "json"
+		}
+	}
+
+	return writeOutput(writer, chain.Value(), format)
+}
+
+// parsePipeline splits the pipeline into operations and validates their arguments
+func parsePipeline(pipeline string) ([]operation, error) {
+	var operations []operation
+
+	if strings.TrimSpace(pipeline) == "" {
+		return operations, nil
+	}
+
+	stages, err := splitPipeline(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fields := range stages {
+		if len(fields) == 0 {
+			return nil, errors.New("empty operation in the pipeline")
+		}
+
+		if last := len(operations) - 1; last >= 0 && operations[last].ends {
+			return nil, fmt.Errorf("%s: can't follow %s, it needs a sequence", fields[0], operations[last].name)
+		}
+
+		op, err := parseOperation(fields[0], fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fields[0], err)
+		}
+
+		operations = append(operations, op)
+	}
+
+	return operations, nil
+}
+
+// splitPipeline splits the pipeline into stages by "|" and stages into fields by spaces,
+// quoted values like "a | b" or paths like .a['b c'] are kept whole with their quotes
+func splitPipeline(pipeline string) ([][]string, error) {
+	stages := [][]string{nil}
+	var field strings.Builder
+	var quote byte
+	inField := false
+
+	endField := func() {
+		if inField {
+			last := len(stages) - 1
+			stages[last] = append(stages[last], field.String())
+			field.Reset()
+			inField = false
+		}
+	}
+
+	for pos := 0; pos < len(pipeline); pos++ {
+		char := pipeline[pos]
+
+		switch {
+		case quote != 0:
+			field.WriteByte(char)
+			if char == '\\' && pos+1 < len(pipeline) {
+				pos++
+				field.WriteByte(pipeline[pos])
+			} else if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			field.WriteByte(char)
+			quote, inField = char, true
+		case char == '|':
+			endField()
+			stages = append(stages, nil)
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			endField()
+		default:
+			field.WriteByte(char)
+			inField = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in the pipeline", quote)
+	}
+
+	endField()
+	return stages, nil
+}
+
+func parseOperation(name string, args []string) (operation, error) {
+	op := operation{name: name}
+	var err error
+
+	switch name {
+	case "uniq":
+		err = expectArgs(args, 0, 1)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.Uniq(createComparator(optionalArg(args, 0, ".")))
+		}
+	case "sortby":
+		err = expectArgs(args, 1, 1)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.SortBy(createComparator(args[0]))
+		}
+	case "top":
+		var n int
+		if err = expectArgs(args, 1, 2); err == nil {
+			n, err = parseCount(args[0])
+		}
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.TopK(n, createComparator(optionalArg(args, 1, ".")))
+		}
+	case "first", "last", "sample":
+		var n int
+		if err = expectArgs(args, 1, 1); err == nil {
+			n, err = parseCount(args[0])
+		}
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			switch name {
+			case "first":
+				return chain.First(n)
+			case "last":
+				return chain.Last(n)
+			}
+			return chain.Sample(n, nil)
+		}
+	case "where":
+		if len(args) < 2 {
+			err = errors.New("expected path and value")
+			break
+		}
+		value := parseValue(strings.Join(args[1:], " "))
+		get := createGetter(args[0])
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.Filter(func(cur, _, _ u.Object) bool { return u.Compare(get(cur, nil, nil), value) == 0 })
+		}
+	case "pluck":
+		err = expectArgs(args, 1, 1)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.Map(createGetter(args[0]))
+		}
+	case "reverse", "shuffle":
+		err = expectArgs(args, 0, 0)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			if name == "reverse" {
+				return chain.Reverse()
+			}
+			return chain.Shuffle()
+		}
+	case "groupby":
+		op.ends = true
+		err = expectArgs(args, 1, 1)
+		get := createGetter(args[0])
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			// arrays and objects can't be the map keys, so groups are keyed by the string form of the value
+			return chain.GroupBy(func(cur, key, src u.Object) u.Object { return createKey(get(cur, key, src)) })
+		}
+	case "countby":
+		op.ends = true
+		err = expectArgs(args, 1, 1)
+		get := createGetter(args[0])
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			return chain.CountBy(func(cur, key, src u.Object) u.Object { return createKey(get(cur, key, src)) })
+		}
+	case "min", "max":
+		op.ends = true
+		err = expectArgs(args, 0, 1)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			// Min and Max return -1 for the empty sequence, it's written as null instead
+			if len(chain.Mid) == 0 {
+				chain.Res = nil
+				chain.Mid = nil
+				return chain
+			}
+			if name == "min" {
+				return chain.Min(createComparator(optionalArg(args, 0, ".")))
+			}
+			return chain.Max(createComparator(optionalArg(args, 0, ".")))
+		}
+	case "count":
+		op.ends = true
+		err = expectArgs(args, 0, 0)
+		op.apply = func(chain *u.ChainWrapper) *u.ChainWrapper {
+			chain.Res = len(chain.Mid)
+			chain.Mid = nil
+			return chain
+		}
+	default:
+		err = errors.New("unknown operation")
+	}
+
+	if err == nil {
+		err = checkPaths(name, args)
+	}
+
+	return op, err
+}
+
+func expectArgs(args []string, min, max int) error {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return fmt.Errorf("expected %d arguments, got %d", min, len(args))
+		}
+		return fmt.Errorf("expected from %d to %d arguments, got %d", min, max, len(args))
+	}
+	return nil
+}
+
+func optionalArg(args []string, index int, fallback string) string {
+	if index < len(args) {
+		return args[index]
+	}
+	return fallback
+}
+
+func parseCount(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("expected non-negative count, got %q", arg)
+	}
+	return n, nil
+}
+
+// parseValue reads the argument as JSON value, falling back to the plain string
+func parseValue(arg string) u.Object {
+	decoder := json.NewDecoder(strings.NewReader(arg))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return arg
+	}
+
+	return value
+}
+
+// createComparator returns the Comparator by path, the leading minus reverses it
+func createComparator(path string) u.Comparator {
+	if strings.HasPrefix(path, "-") {
+		return u.NegateComparator(u.CompareBy(createGetter(path[1:])))
+	}
+	return u.CompareBy(createGetter(path))
+}
+
+// createGetter returns the Callback, which takes the value of path like .a.b[0] from the element
+func createGetter(path string) u.Callback {
+	return u.PathCallback(trimPath(path))
+}
+
+// trimPath returns the path without the leading minus and dot, so the single dot becomes the empty path
+func trimPath(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "-"), ".")
+}
+
+// pathArgs are positions of path arguments of the operations
+var pathArgs = map[string][]int{
+	"uniq": {0}, "sortby": {0}, "top": {1}, "where": {0}, "pluck": {0},
+	"groupby": {0}, "countby": {0}, "min": {0}, "max": {0},
+}
+
+func checkPaths(name string, args []string) error {
+	for _, index := range pathArgs[name] {
+		if index >= len(args) {
+			continue
+		}
+
+		if _, err := u.ParsePath(trimPath(args[index])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createKey returns the string key of the group
+func createKey(value u.Object) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case nil:
+		return "null"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// readInput reads JSON array or JSON Lines, numbers are kept as json.Number, so they're written back untouched
+func readInput(reader io.Reader) (u.Seq, bool, error) {
+	buffered := bufio.NewReader(reader)
+
+	for {
+		next, err := buffered.Peek(1)
+		if err == io.EOF {
+			return u.Seq{}, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		if next[0] == ' ' || next[0] == '\t' || next[0] == '\r' || next[0] == '\n' {
+			buffered.Discard(1)
+			continue
+		}
+
+		if next[0] != '[' {
+			seq, err := u.ReadJSONL(buffered, u.JSONOptions{Numbers: u.NumberJSON})
+			return seq, false, err
+		}
+
+		data, err := io.ReadAll(buffered)
+		if err != nil {
+			return nil, true, err
+		}
+
+		// JSON Lines of arrays start with "[" as well, but have more values after the first one
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		var first json.RawMessage
+		if err := decoder.Decode(&first); err == nil && decoder.More() {
+			seq, err := u.ReadJSONL(bytes.NewReader(data), u.JSONOptions{Numbers: u.NumberJSON})
+			return seq, false, err
+		}
+
+		seq, err := u.DecodeJSON(data, u.JSONOptions{Numbers: u.NumberJSON})
+		return seq, true, err
+	}
+}
+
+func writeOutput(writer io.Writer, result u.Object, format string) error {
+	buffered := bufio.NewWriter(writer)
+
+	if groups, ok := result.(map[u.Object]u.Seq); ok {
+		keyed := make(map[string]u.Seq, len(groups))
+		for key, group := range groups {
+			keyed[createKey(key)] = group
+		}
+		result = keyed
+	}
+
+	var err error
+	if seq, ok := result.(u.Seq); ok && format == "jsonl" {
+		err = u.WriteJSONL(buffered, seq, u.JSONOptions{})
+	} else {
+		var data []byte
+		if data, err = json.Marshal(result); err == nil {
+			_, err = buffered.Write(append(data, '\n'))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
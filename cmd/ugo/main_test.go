@@ -0,0 +1,163 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	. "github.com/franela/goblin"
+	"strings"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	g := Goblin(t)
+
+	players := `{"id": 1, "team": "red", "score": 5}
+{"id": 2, "team": "blue", "score": 9}
+{"id": 1, "team": "red", "score": 5}
+{"id": 3, "team": "red", "score": 7.5, "stats": {"goals": [4, 2]}}
+`
+
+	execute := func(input, pipeline, format string) (string, error) {
+		var out bytes.Buffer
+		err := run(strings.NewReader(input), &out, pipeline, format)
+		return out.String(), err
+	}
+
+	g.Describe("#run()", func() {
+		g.It("Should apply the pipeline to JSON Lines", func() {
+			out, err := execute(players, "uniq .id | sortby -.score | first 10 | groupby .team", "")
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal(`{"blue":[{"id":2,"score":9,"team":"blue"}],"red":[{"id":3,"score":7.5,"stats":{"goals":[4,2]},"team":"red"},{"id":1,"score":5,"team":"red"}]}` + "\n")
+		})
+
+		g.It("Should keep the input format", func() {
+			out, err := execute(players, "where .team red | pluck .id | uniq | top 2", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("3\n1\n")
+
+			out, err = execute(" [3, 1.50, 2] ", "sortby -.", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("[3,2,1.50]\n")
+
+			out, err = execute("[3, 1, 2]", "reverse | last 2", "jsonl")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("1\n3\n")
+
+			out, err = execute("[1, 2]\n[3]\n", "reverse", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("[3]\n[1,2]\n")
+		})
+
+		g.It("Should end the pipeline with aggregating operations", func() {
+			out, _ := execute(players, "countby .team", "")
+			g.Assert(out).Equal(`{"blue":1,"red":3}` + "\n")
+
+			out, _ = execute(players, "max .stats.goals.0", "")
+			g.Assert(out).Equal(`{"id":3,"score":7.5,"stats":{"goals":[4,2]},"team":"red"}` + "\n")
+
+			out, _ = execute(players, "where .stats.goals[-1] 2 | pluck .id", "")
+			g.Assert(out).Equal("3\n")
+
+			out, _ = execute(players, "count", "json")
+			g.Assert(out).Equal("4\n")
+
+			out, _ = execute("", "", "")
+			g.Assert(out).Equal("")
+		})
+
+		g.It("Should group by array and object values", func() {
+			input := `{"id": 1, "tags": ["a"]}
+{"id": 2, "tags": ["a", "b"]}
+{"id": 3, "tags": ["a"]}
+{"id": 4, "tags": {"a": 1}}
+`
+
+			out, err := execute(input, "groupby .tags", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal(`{"[\"a\",\"b\"]":[{"id":2,"tags":["a","b"]}],"[\"a\"]":[{"id":1,"tags":["a"]},{"id":3,"tags":["a"]}],"{\"a\":1}":[{"id":4,"tags":{"a":1}}]}` + "\n")
+		})
+
+		g.It("Should write null for min and max of the empty input", func() {
+			out, err := execute("[]", "min", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("null\n")
+
+			out, err = execute(players, "where .team green | max .score", "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("null\n")
+		})
+
+		g.It("Should keep quoted values and paths whole", func() {
+			input := `{"id": 1, "name": "a|b", "tags": {"x | y": 3}}
+{"id": 2, "name": "a  b", "tags": {"x | y": 4}}
+`
+
+			out, err := execute(input, `where .name "a|b" | pluck .id`, "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("1\n")
+
+			out, err = execute(input, `where .name "a  b" | pluck .id`, "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("2\n")
+
+			out, err = execute(input, `pluck .tags['x | y'] | max .`, "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("4\n")
+
+			out, err = execute(input, `where .name "a\"|" | count`, "")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(out).Equal("0\n")
+
+			_, err = execute(input, `where .name "a|b`, "")
+			g.Assert(err.Error()).Equal("unterminated \" quote in the pipeline")
+		})
+
+		g.It("Should return errors for the wrong pipeline", func() {
+			_, err := execute(players, "groupby .team | first 1", "")
+			g.Assert(err.Error()).Equal("first: can't follow groupby, it needs a sequence")
+
+			_, err = execute(players, "max | ", "")
+			g.Assert(err.Error()).Equal("empty operation in the pipeline")
+
+			_, err = execute(players, "first", "")
+			g.Assert(err.Error()).Equal("first: expected 1 arguments, got 0")
+
+			_, err = execute(players, "top -1", "")
+			g.Assert(err.Error()).Equal(`top: expected non-negative count, got "-1"`)
+
+			_, err = execute(players, "sortby -.stats[", "")
+			g.Assert(err.Error()).Equal(`sortby: ugo: path "stats[" at column 6: unterminated [`)
+
+			_, err = execute(players, "explode", "")
+			g.Assert(err.Error()).Equal("explode: unknown operation")
+
+			_, err = execute(players, "count", "yaml")
+			g.Assert(err.Error()).Equal(`unknown output format "yaml"`)
+
+			_, err = execute("{oops}", "count", "")
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+}
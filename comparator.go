@@ -0,0 +1,177 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// comparing ranks of the value kinds, values of lower rank go first
+const (
+	rankNil = iota
+	rankBool
+	rankNumber
+	rankString
+	rankOther
+)
+
+// Compare is the natural Comparator, it orders values as nil, booleans, numbers, strings and the rest of them,
+// numbers of any type (including json.Number) are compared by their values, booleans as false before true,
+// strings lexicographically and the rest of values by their fmt representation
+func Compare(left, right Object) int {
+	leftRank, rightRank := createRank(left), createRank(right)
+
+	if leftRank != rightRank {
+		return createSign(leftRank - rightRank)
+	}
+
+	switch leftRank {
+	case rankNil:
+		return equal
+	case rankBool:
+		return createSign(createBoolRank(left) - createBoolRank(right))
+	case rankNumber:
+		return createNumberComparison(left, right)
+	case rankString:
+		return strings.Compare(reflect.ValueOf(left).String(), reflect.ValueOf(right).String())
+	}
+
+	return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+}
+
+// CompareBy returns the Comparator, which compares results of the Callback by #Compare,
+// the Callback receives -1 as currentKey and nil as src
+func CompareBy(cb Callback) Comparator {
+	if cb == nil {
+		return Compare
+	}
+
+	return func(left, right Object) int {
+		return Compare(cb(left, -1, nil), cb(right, -1, nil))
+	}
+}
+
+/* private methods */
+func createRank(value Object) int {
+	if value == nil {
+		return rankNil
+	}
+
+	if _, ok := value.(json.Number); ok {
+		return rankNumber
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool:
+		return rankBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return rankNumber
+	case reflect.String:
+		return rankString
+	}
+
+	return rankOther
+}
+
+func createBoolRank(value Object) int {
+	if reflect.ValueOf(value).Bool() {
+		return 1
+	}
+	return 0
+}
+
+func createSign(diff int) int {
+	switch {
+	case diff < 0:
+		return less
+	case diff > 0:
+		return larger
+	}
+	return equal
+}
+
+// createNumberComparison compares integers exactly and falls back to float64 for the rest of them
+func createNumberComparison(left, right Object) int {
+	leftInt, leftIsInt := toInt64(left)
+	rightInt, rightIsInt := toInt64(right)
+
+	if leftIsInt && rightIsInt {
+		switch {
+		case leftInt < rightInt:
+			return less
+		case leftInt > rightInt:
+			return larger
+		}
+		return equal
+	}
+
+	leftFloat, _ := toComparableFloat64(left)
+	rightFloat, _ := toComparableFloat64(right)
+
+	switch {
+	case leftFloat < rightFloat:
+		return less
+	case leftFloat > rightFloat:
+		return larger
+	case math.IsNaN(leftFloat) && !math.IsNaN(rightFloat):
+		return less
+	case !math.IsNaN(leftFloat) && math.IsNaN(rightFloat):
+		return larger
+	}
+	return equal
+}
+
+// toInt64 returns integer value of signed integers, unsigned integers, which fit into int64, and integral json.Number
+func toInt64(value Object) (int64, bool) {
+	if number, ok := value.(json.Number); ok {
+		integral, err := number.Int64()
+		return integral, err == nil
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if unsigned := reflected.Uint(); unsigned <= math.MaxInt64 {
+			return int64(unsigned), true
+		}
+	}
+
+	return 0, false
+}
+
+func toComparableFloat64(value Object) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		float, err := number.Float64()
+		return float, err == nil
+	}
+	return toFloat64(value)
+}
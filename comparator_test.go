@@ -0,0 +1,78 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"encoding/json"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math"
+	"testing"
+)
+
+func TestComparators(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#Compare()", func() {
+		g.It("Should compare numbers of different types by value", func() {
+			g.Assert(Compare(1, 2)).Equal(-1)
+			g.Assert(Compare(int8(3), 2.5)).Equal(1)
+			g.Assert(Compare(uint64(7), int32(7))).Equal(0)
+			g.Assert(Compare(json.Number("10"), 9)).Equal(1)
+			g.Assert(Compare(json.Number("1.5"), float32(1.5))).Equal(0)
+			g.Assert(Compare(int64(math.MaxInt64), int64(math.MaxInt64-1))).Equal(1)
+			g.Assert(Compare(uint64(math.MaxUint64), int64(math.MaxInt64))).Equal(1)
+			g.Assert(Compare(math.NaN(), 0)).Equal(-1)
+		})
+
+		g.It("Should order values of different kinds", func() {
+			seq := Seq{"b", 2, nil, true, Seq{1}, "a", false, 1.5}
+
+			g.Assert(SortBy(seq, Compare)).Equal(Seq{nil, false, true, 1.5, 2, "a", "b", Seq{1}})
+		})
+
+		g.It("Should compare strings lexicographically", func() {
+			g.Assert(Compare("abc", "abd")).Equal(-1)
+			g.Assert(Compare("b", "abc")).Equal(1)
+			g.Assert(Compare("", "")).Equal(0)
+		})
+	})
+
+	g.Describe("#CompareBy()", func() {
+		g.It("Should compare results of the Callback", func() {
+			byScore := CompareBy(func(cur, _, _ Object) Object { return cur.(map[string]Object)["score"] })
+			seq := Seq{
+				map[string]Object{"score": 3},
+				map[string]Object{"score": 1.5},
+				map[string]Object{},
+			}
+
+			g.Assert(SortBy(seq, byScore)).Equal(Seq{
+				map[string]Object{},
+				map[string]Object{"score": 1.5},
+				map[string]Object{"score": 3},
+			})
+			g.Assert(CompareBy(nil)(1, 2)).Equal(-1)
+		})
+	})
+}
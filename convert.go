@@ -0,0 +1,296 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// To converts Seq into the slice of given type, it returns error if any of the values has another type,
+// nil values become zero values of the types, which can be nil (pointers, interfaces, maps, slices, etc.)
+func To[T any](seq Seq) ([]T, error) {
+	result := make([]T, len(seq))
+	nillable := isNillable(reflect.TypeOf((*T)(nil)).Elem())
+
+	for index, value := range seq {
+		if typed, ok := value.(T); ok {
+			result[index] = typed
+		} else if value != nil || !nillable {
+			return nil, createConvertError(index, value, reflect.TypeOf((*T)(nil)).Elem())
+		}
+	}
+
+	return result, nil
+}
+
+// Into converts Seq into the slice or array, which the pointer refers to (see #To), the slice is replaced,
+// the array must have the same length as Seq, the target is left untouched when error is returned
+func Into(seq Seq, target Object) error {
+	switch typed := target.(type) {
+	case *Seq:
+		if typed != nil {
+			*typed = createCopy(seq)
+			return nil
+		}
+	case *[]interface{}:
+		return createInto(seq, typed)
+	case *[]int:
+		return createInto(seq, typed)
+	case *[]int64:
+		return createInto(seq, typed)
+	case *[]float64:
+		return createInto(seq, typed)
+	case *[]string:
+		return createInto(seq, typed)
+	case *[]bool:
+		return createInto(seq, typed)
+	case *[]byte:
+		return createInto(seq, typed)
+	}
+
+	reflected := reflect.ValueOf(target)
+	if reflected.Kind() != reflect.Ptr || reflected.IsNil() {
+		return fmt.Errorf("ugo: Into expects the non-nil pointer to slice or array, got %T", target)
+	}
+
+	reflected = reflected.Elem()
+
+	switch reflected.Kind() {
+	case reflect.Slice:
+		result := reflect.MakeSlice(reflected.Type(), len(seq), len(seq))
+		if err := fillReflected(seq, result); err != nil {
+			return err
+		}
+		reflected.Set(result)
+	case reflect.Array:
+		if reflected.Len() != len(seq) {
+			return fmt.Errorf("ugo: Into can't put %d values into the array of length %d", len(seq), reflected.Len())
+		}
+		result := reflect.New(reflected.Type()).Elem()
+		if err := fillReflected(seq, result); err != nil {
+			return err
+		}
+		reflected.Set(result)
+	default:
+		return fmt.Errorf("ugo: Into expects the non-nil pointer to slice or array, got %T", target)
+	}
+
+	return nil
+}
+
+/* private methods */
+// createSeqFrom returns Seq of at most limit values of target, negative limit takes all of them
+func createSeqFrom(target Object, limit int) Seq {
+	switch values := target.(type) {
+	case nil:
+		return Seq{}
+	case Seq:
+		return createSeqOf(values, limit)
+	case []interface{}:
+		return createSeqOf(values, limit)
+	case []int:
+		return createSeqOf(values, limit)
+	case []int8:
+		return createSeqOf(values, limit)
+	case []int16:
+		return createSeqOf(values, limit)
+	case []int32:
+		return createSeqOf(values, limit)
+	case []int64:
+		return createSeqOf(values, limit)
+	case []uint:
+		return createSeqOf(values, limit)
+	case []byte:
+		return createSeqOf(values, limit)
+	case []uint16:
+		return createSeqOf(values, limit)
+	case []uint32:
+		return createSeqOf(values, limit)
+	case []uint64:
+		return createSeqOf(values, limit)
+	case []float32:
+		return createSeqOf(values, limit)
+	case []float64:
+		return createSeqOf(values, limit)
+	case []string:
+		return createSeqOf(values, limit)
+	case []bool:
+		return createSeqOf(values, limit)
+	case Iterator:
+		return createSeqFromIterator(values, limit)
+	case func(func(interface{}) bool):
+		return createSeqFromIterator(values, limit)
+	case chan Object:
+		return createSeqFromChan(values, limit)
+	case <-chan Object:
+		return createSeqFromChan(values, limit)
+	}
+
+	return createSeqFromReflected(reflect.ValueOf(target), limit)
+}
+
+func createSeqOf[T any](values []T, limit int) Seq {
+	length := len(values)
+	if limit >= 0 && limit < length {
+		length = limit
+	}
+
+	result := make(Seq, length)
+	for index := 0; index < length; index++ {
+		result[index] = values[index]
+	}
+
+	return result
+}
+
+func createSeqFromIterator[T any](iterator func(func(T) bool), limit int) Seq {
+	result := Seq{}
+	if limit == 0 {
+		return result
+	}
+
+	iterator(func(value T) bool {
+		result = append(result, value)
+		return limit < 0 || len(result) < limit
+	})
+
+	return result
+}
+
+func createSeqFromChan[T any](values <-chan T, limit int) Seq {
+	result := Seq{}
+
+	for limit < 0 || len(result) < limit {
+		value, ok := <-values
+		if !ok {
+			break
+		}
+		result = append(result, value)
+	}
+
+	return result
+}
+
+// createSeqFromReflected handles arrays, slices, maps, channels and iterators of any types
+func createSeqFromReflected(reflected reflect.Value, limit int) Seq {
+	result := Seq{}
+	isFull := func() bool { return limit >= 0 && len(result) >= limit }
+
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < reflected.Len() && !isFull(); index++ {
+			result = append(result, reflected.Index(index).Interface())
+		}
+	case reflect.Map:
+		keys := reflected.MapKeys()
+		pairs := make(Seq, len(keys))
+		for index, key := range keys {
+			pairs[index] = Seq{key.Interface(), reflected.MapIndex(key).Interface()}
+		}
+
+		SortBy(pairs, func(left, right Object) int { return Compare(left.(Seq)[0], right.(Seq)[0]) })
+		result = createSeqOf(pairs, limit)
+	case reflect.Chan:
+		if reflected.Type().ChanDir()&reflect.RecvDir == 0 {
+			return result
+		}
+		for !isFull() {
+			value, ok := reflected.Recv()
+			if !ok {
+				break
+			}
+			result = append(result, value.Interface())
+		}
+	case reflect.Func:
+		if !isIteratorType(reflected.Type()) || reflected.IsNil() || limit == 0 {
+			return result
+		}
+
+		yield := reflect.MakeFunc(reflected.Type().In(0), func(args []reflect.Value) []reflect.Value {
+			result = append(result, args[0].Interface())
+			return []reflect.Value{reflect.ValueOf(!isFull())}
+		})
+		reflected.Call([]reflect.Value{yield})
+	}
+
+	return result
+}
+
+// isIteratorType checks whether the function has the form of func(yield func(T) bool)
+func isIteratorType(kind reflect.Type) bool {
+	if kind.NumIn() != 1 || kind.NumOut() != 0 || kind.In(0).Kind() != reflect.Func {
+		return false
+	}
+
+	yield := kind.In(0)
+	return yield.NumIn() == 1 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+func createInto[T any](seq Seq, target *[]T) error {
+	if target == nil {
+		return fmt.Errorf("ugo: Into expects the non-nil pointer to slice or array, got %T", target)
+	}
+
+	result, err := To[T](seq)
+	if err != nil {
+		return err
+	}
+
+	*target = result
+	return nil
+}
+
+// fillReflected sets the values of Seq to the elements of slice or array
+func fillReflected(seq Seq, target reflect.Value) error {
+	kind := target.Type().Elem()
+
+	for index, value := range seq {
+		if value == nil {
+			if !isNillable(kind) {
+				return createConvertError(index, value, kind)
+			}
+			continue
+		}
+
+		reflected := reflect.ValueOf(value)
+		if !reflected.Type().AssignableTo(kind) {
+			return createConvertError(index, value, kind)
+		}
+		target.Index(index).Set(reflected)
+	}
+
+	return nil
+}
+
+func isNillable(kind reflect.Type) bool {
+	switch kind.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+func createConvertError(index int, value Object, kind reflect.Type) error {
+	return fmt.Errorf("ugo: value at index %d is %T, not %s", index, value, kind)
+}
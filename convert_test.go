@@ -0,0 +1,142 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+type convertID int
+
+func TestConvert(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#From()", func() {
+		g.It("Should infer the length of slices and arrays", func() {
+			g.Assert(From([]int{1, 2, 3})).Equal(Seq{1, 2, 3})
+			g.Assert(From([]string{"a", "b"})).Equal(Seq{"a", "b"})
+			g.Assert(From([]float64{1.5})).Equal(Seq{1.5})
+			g.Assert(From([]byte("ab"))).Equal(Seq{byte('a'), byte('b')})
+			g.Assert(From([]convertID{1, 2})).Equal(Seq{convertID(1), convertID(2)})
+			g.Assert(From([2]bool{true, false})).Equal(Seq{true, false})
+		})
+
+		g.It("Should take at most size values", func() {
+			g.Assert(From([]int{1, 2, 3}, 2)).Equal(Seq{1, 2})
+			g.Assert(From([]int{1, 2, 3}, 5)).Equal(Seq{1, 2, 3})
+			g.Assert(From([2]int{1, 2}, 1)).Equal(Seq{1})
+			g.Assert(From([]int{1, 2, 3}, -1)).Equal(Seq{})
+		})
+
+		g.It("Should turn maps into pairs ordered by keys", func() {
+			g.Assert(From(map[string]int{"b": 2, "a": 1})).Equal(Seq{Seq{"a", 1}, Seq{"b", 2}})
+		})
+
+		g.It("Should read channels until they are closed", func() {
+			values := make(chan int, 3)
+			values <- 1
+			values <- 2
+			values <- 3
+			close(values)
+
+			g.Assert(From(values, 2)).Equal(Seq{1, 2})
+			g.Assert(From(values)).Equal(Seq{3})
+
+			objects := make(chan Object, 1)
+			objects <- "a"
+			close(objects)
+
+			g.Assert(From(objects)).Equal(Seq{"a"})
+			g.Assert(From(make(chan<- int))).Equal(Seq{})
+		})
+
+		g.It("Should collect values of iterators", func() {
+			iterator := Iterator(func(yield func(Object) bool) {
+				for index := 0; index < 5 && yield(index); index++ {
+				}
+			})
+			typed := func(yield func(string) bool) {
+				_ = yield("a") && yield("b")
+			}
+
+			g.Assert(From(iterator)).Equal(Seq{0, 1, 2, 3, 4})
+			g.Assert(From(iterator, 2)).Equal(Seq{0, 1})
+			g.Assert(From(typed)).Equal(Seq{"a", "b"})
+			g.Assert(From(typed, 1)).Equal(Seq{"a"})
+			g.Assert(From(func() {})).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#To()", func() {
+		g.It("Should convert Seq into the typed slice", func() {
+			ints, err := To[int](Seq{1, 2, 3})
+			g.Assert(err == nil).IsTrue()
+			g.Assert(ints).Equal([]int{1, 2, 3})
+
+			pointers, err := To[*int](Seq{nil})
+			g.Assert(err == nil).IsTrue()
+			g.Assert(pointers[0] == nil).IsTrue()
+		})
+
+		g.It("Should return error for the values of other types", func() {
+			_, err := To[int](Seq{1, "2"})
+			g.Assert(err.Error()).Equal("ugo: value at index 1 is string, not int")
+
+			_, err = To[string](Seq{nil})
+			g.Assert(err.Error()).Equal("ugo: value at index 0 is <nil>, not string")
+		})
+	})
+
+	g.Describe("#Into()", func() {
+		g.It("Should fill slices and arrays", func() {
+			var strings []string
+			g.Assert(Into(Seq{"a", "b"}, &strings) == nil).IsTrue()
+			g.Assert(strings).Equal([]string{"a", "b"})
+
+			var ids []convertID
+			g.Assert(Into(Seq{convertID(3)}, &ids) == nil).IsTrue()
+			g.Assert(ids).Equal([]convertID{3})
+
+			var pair [2]int
+			g.Assert(Into(Seq{4, 5}, &pair) == nil).IsTrue()
+			g.Assert(pair).Equal([2]int{4, 5})
+
+			var seq Seq
+			g.Assert(Into(Seq{1, "a"}, &seq) == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{1, "a"})
+		})
+
+		g.It("Should return error and keep the target untouched", func() {
+			ids := []convertID{1}
+			g.Assert(Into(Seq{convertID(2), 3}, &ids).Error()).Equal("ugo: value at index 1 is int, not ugo_test.convertID")
+			g.Assert(ids).Equal([]convertID{1})
+
+			var pair [2]int
+			g.Assert(Into(Seq{1}, &pair).Error()).Equal("ugo: Into can't put 1 values into the array of length 2")
+			g.Assert(Into(Seq{1}, []int{}).Error()).Equal("ugo: Into expects the non-nil pointer to slice or array, got []int")
+			g.Assert(Into(Seq{1}, (*[]int)(nil)).Error()).Equal("ugo: Into expects the non-nil pointer to slice or array, got *[]int")
+		})
+	})
+}
@@ -0,0 +1,347 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time, used in Debounce and Throttle
+type Clock interface {
+	Now() time.Time                                 // Now returns the current time
+	AfterFunc(wait time.Duration, fn func()) Ticket // AfterFunc calls fn after wait in its own goroutine
+}
+
+// Ticket is the handle of the call, scheduled by Clock
+type Ticket interface {
+	Stop() bool // Stop cancels the call, returns false if it has been already done or stopped
+}
+
+// SystemClock is the Clock, which uses real time
+type SystemClock struct{}
+
+// ManualClock is the Clock, which goes forward only when it's advanced,
+// so the calls it schedules are done synchronously inside #Advance
+type ManualClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	tickets []*manualTicket
+}
+
+// Debounced is the special struct, which postpones the Action until wait duration
+// has passed since the last call, so the Action gets only arguments of the last call
+type Debounced struct {
+	mutex      sync.Mutex
+	cb         Action
+	wait       time.Duration
+	clock      Clock
+	ticket     Ticket
+	generation uint64 // generation changes every time the ticket is stopped, so the stale call is skipped
+	pending    Seq
+}
+
+// Throttled is the special struct, which calls the Action at most once per wait duration
+type Throttled struct {
+	mutex      sync.Mutex
+	cb         Action
+	wait       time.Duration
+	leading    bool
+	trailing   bool
+	clock      Clock
+	ticket     Ticket
+	generation uint64 // generation changes every time the ticket is stopped, so the stale call is skipped
+	pending    Seq
+	previous   time.Time
+}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls fn after wait in its own goroutine
+func (SystemClock) AfterFunc(wait time.Duration, fn func()) Ticket {
+	return time.AfterFunc(wait, fn)
+}
+
+// NewManualClock creates a new ManualClock, which shows given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of ManualClock
+func (clock *ManualClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+// AfterFunc schedules fn to be called, when ManualClock is advanced by wait
+func (clock *ManualClock) AfterFunc(wait time.Duration, fn func()) Ticket {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	ticket := &manualTicket{clock: clock, at: clock.now.Add(wait), fn: fn}
+	clock.tickets = append(clock.tickets, ticket)
+
+	return ticket
+}
+
+// Advance moves ManualClock forward by given duration,
+// calling all of the scheduled functions, which time has come, in order of their time
+func (clock *ManualClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	target := clock.now.Add(duration)
+	clock.mutex.Unlock()
+
+	for {
+		clock.mutex.Lock()
+		sort.SliceStable(clock.tickets, func(i, j int) bool { return clock.tickets[i].at.Before(clock.tickets[j].at) })
+
+		if len(clock.tickets) == 0 || clock.tickets[0].at.After(target) {
+			clock.now = target
+			clock.mutex.Unlock()
+			return
+		}
+
+		ticket := clock.tickets[0]
+		clock.tickets = clock.tickets[1:]
+		clock.now = ticket.at
+		clock.mutex.Unlock()
+
+		ticket.fn()
+	}
+}
+
+// Debounce returns Debounced, which calls the Action only after wait duration since the last call,
+// if Clock is not passed, it uses SystemClock
+func Debounce(cb Action, wait time.Duration, clock Clock) *Debounced {
+	if cb == nil {
+		return nil
+	}
+	return &Debounced{cb: cb, wait: wait, clock: fixClock(clock)}
+}
+
+// Call postpones the Action with given arguments, it satisfies the Action type
+func (debounced *Debounced) Call(cur, index, list Object) {
+	debounced.mutex.Lock()
+	defer debounced.mutex.Unlock()
+
+	debounced.stop()
+
+	generation := debounced.generation
+	debounced.pending = Seq{cur, index, list}
+	debounced.ticket = debounced.clock.AfterFunc(debounced.wait, func() { debounced.fire(generation) })
+}
+
+// Cancel drops the postponed call, if there is one
+func (debounced *Debounced) Cancel() {
+	debounced.mutex.Lock()
+	defer debounced.mutex.Unlock()
+
+	debounced.stop()
+	debounced.pending = nil
+}
+
+// Flush does the postponed call right now, if there is one
+func (debounced *Debounced) Flush() {
+	debounced.mutex.Lock()
+	debounced.stop()
+	generation := debounced.generation
+	debounced.mutex.Unlock()
+
+	debounced.fire(generation)
+}
+
+// IsPending returns true if there is a postponed call
+func (debounced *Debounced) IsPending() bool {
+	debounced.mutex.Lock()
+	defer debounced.mutex.Unlock()
+	return debounced.pending != nil
+}
+
+// Throttle returns Throttled, which calls the Action at most once per wait duration,
+// leading allows the call at the start of wait duration, trailing allows the call
+// at the end of it with arguments of the last call, if Clock is not passed, it uses SystemClock
+func Throttle(cb Action, wait time.Duration, leading, trailing bool, clock Clock) *Throttled {
+	if cb == nil {
+		return nil
+	}
+	return &Throttled{cb: cb, wait: wait, leading: leading, trailing: trailing, clock: fixClock(clock)}
+}
+
+// Call does the Action with given arguments, if wait duration has passed since the previous one,
+// otherwise it may postpone it to the end of wait duration, it satisfies the Action type
+func (throttled *Throttled) Call(cur, index, list Object) {
+	throttled.mutex.Lock()
+
+	now := throttled.clock.Now()
+	if throttled.previous.IsZero() && !throttled.leading {
+		throttled.previous = now
+	}
+
+	remaining := throttled.wait - now.Sub(throttled.previous)
+
+	if remaining <= 0 || remaining > throttled.wait {
+		throttled.stop()
+		throttled.previous = now
+		throttled.pending = nil
+		throttled.mutex.Unlock()
+
+		throttled.cb(cur, index, list)
+		return
+	}
+
+	if throttled.trailing {
+		throttled.pending = Seq{cur, index, list}
+		if throttled.ticket == nil {
+			generation := throttled.generation
+			throttled.ticket = throttled.clock.AfterFunc(remaining, func() { throttled.fire(generation) })
+		}
+	}
+
+	throttled.mutex.Unlock()
+}
+
+// Cancel drops the postponed call, if there is one, and resets the wait duration
+func (throttled *Throttled) Cancel() {
+	throttled.mutex.Lock()
+	defer throttled.mutex.Unlock()
+
+	throttled.stop()
+	throttled.pending = nil
+	throttled.previous = time.Time{}
+}
+
+// Flush does the postponed call right now, if there is one
+func (throttled *Throttled) Flush() {
+	throttled.mutex.Lock()
+	throttled.stop()
+	generation := throttled.generation
+	throttled.mutex.Unlock()
+
+	throttled.fire(generation)
+}
+
+// IsPending returns true if there is a postponed call
+func (throttled *Throttled) IsPending() bool {
+	throttled.mutex.Lock()
+	defer throttled.mutex.Unlock()
+	return throttled.pending != nil
+}
+
+/* private methods */
+// manualTicket is the call, scheduled by ManualClock
+type manualTicket struct {
+	clock *ManualClock
+	at    time.Time
+	fn    func()
+}
+
+func (ticket *manualTicket) Stop() bool {
+	ticket.clock.mutex.Lock()
+	defer ticket.clock.mutex.Unlock()
+
+	for index, scheduled := range ticket.clock.tickets {
+		if scheduled == ticket {
+			ticket.clock.tickets = append(ticket.clock.tickets[:index], ticket.clock.tickets[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// stop cancels the scheduled call of Debounced, the mutex should be locked
+func (debounced *Debounced) stop() {
+	if debounced.ticket != nil {
+		debounced.ticket.Stop()
+	}
+	debounced.ticket = nil
+	debounced.generation++
+}
+
+// fire does the postponed call of Debounced, unless the ticket of given generation has been stopped,
+// the stopped one still may fire, if it was waiting for the mutex
+func (debounced *Debounced) fire(generation uint64) {
+	debounced.mutex.Lock()
+	if generation != debounced.generation {
+		debounced.mutex.Unlock()
+		return
+	}
+
+	args := debounced.pending
+	debounced.pending = nil
+	debounced.ticket = nil
+	debounced.mutex.Unlock()
+
+	if args != nil {
+		debounced.cb(args[0], args[1], args[2])
+	}
+}
+
+// stop cancels the scheduled call of Throttled, the mutex should be locked
+func (throttled *Throttled) stop() {
+	if throttled.ticket != nil {
+		throttled.ticket.Stop()
+	}
+	throttled.ticket = nil
+	throttled.generation++
+}
+
+// fire does the postponed call of Throttled, unless the ticket of given generation has been stopped
+func (throttled *Throttled) fire(generation uint64) {
+	throttled.mutex.Lock()
+	if generation != throttled.generation {
+		throttled.mutex.Unlock()
+		return
+	}
+
+	args := throttled.pending
+	throttled.pending = nil
+	throttled.ticket = nil
+
+	if args == nil {
+		throttled.mutex.Unlock()
+		return
+	}
+
+	if throttled.leading {
+		throttled.previous = throttled.clock.Now()
+	} else {
+		throttled.previous = time.Time{}
+	}
+	throttled.mutex.Unlock()
+
+	throttled.cb(args[0], args[1], args[2])
+}
+
+// fixClock returns given Clock or SystemClock if it's nil
+func fixClock(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock{}
+	}
+	return clock
+}
@@ -0,0 +1,269 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+// staleClock is ManualClock, which keeps every scheduled function, so the stopped one can be called
+// the way it happens, when the timer has already fired and waits for the mutex
+type staleClock struct {
+	*ManualClock
+	scheduled []func()
+}
+
+func (clock *staleClock) AfterFunc(wait time.Duration, fn func()) Ticket {
+	clock.scheduled = append(clock.scheduled, fn)
+	return clock.ManualClock.AfterFunc(wait, fn)
+}
+
+func TestDebounce(t *testing.T) {
+	g := Goblin(t)
+
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g.Describe("#ManualClock", func() {
+		g.It("Should call scheduled functions in order of their time", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+
+			clock.AfterFunc(30*time.Millisecond, func() { calls = append(calls, 30) })
+			clock.AfterFunc(10*time.Millisecond, func() { calls = append(calls, 10) })
+			stopped := clock.AfterFunc(20*time.Millisecond, func() { calls = append(calls, 20) })
+
+			g.Assert(stopped.Stop()).IsTrue()
+			g.Assert(stopped.Stop()).IsFalse()
+
+			clock.Advance(15 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{10})
+			g.Assert(clock.Now()).Equal(start.Add(15 * time.Millisecond))
+
+			clock.Advance(15 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{10, 30})
+		})
+	})
+
+	g.Describe("#Debounce()", func() {
+		g.It("Should call the Action once with arguments of the last call", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			debounced := Debounce(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, clock)
+
+			debounced.Call(1, 0, nil)
+			clock.Advance(50 * time.Millisecond)
+			debounced.Call(2, 1, nil)
+			clock.Advance(50 * time.Millisecond)
+			debounced.Call(3, 2, nil)
+
+			g.Assert(calls).Equal(Seq{})
+			g.Assert(debounced.IsPending()).IsTrue()
+
+			clock.Advance(99 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{})
+
+			clock.Advance(time.Millisecond)
+			g.Assert(calls).Equal(Seq{3})
+			g.Assert(debounced.IsPending()).IsFalse()
+		})
+
+		g.It("Should drop the postponed call on Cancel", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			debounced := Debounce(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, clock)
+
+			debounced.Call(1, 0, nil)
+			debounced.Cancel()
+			clock.Advance(time.Second)
+
+			g.Assert(calls).Equal(Seq{})
+		})
+
+		g.It("Should do the postponed call right away on Flush", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			debounced := Debounce(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, clock)
+
+			debounced.Flush()
+			g.Assert(calls).Equal(Seq{})
+
+			debounced.Call(1, 0, nil)
+			debounced.Flush()
+			g.Assert(calls).Equal(Seq{1})
+
+			clock.Advance(time.Second)
+			g.Assert(calls).Equal(Seq{1})
+		})
+
+		g.It("Should skip the stale call, which fires after the new one is scheduled", func() {
+			clock := &staleClock{ManualClock: NewManualClock(start)}
+			calls := Seq{}
+			debounced := Debounce(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, clock)
+
+			debounced.Call(1, 0, nil)
+			debounced.Call(2, 1, nil)
+			clock.scheduled[0]()
+
+			g.Assert(calls).Equal(Seq{})
+			g.Assert(debounced.IsPending()).IsTrue()
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{2})
+		})
+
+		g.It("Should work with SystemClock", func() {
+			done := make(chan Object, 1)
+			debounced := Debounce(func(cur, _, _ Object) { done <- cur }, time.Millisecond, nil)
+
+			debounced.Call(1, 0, nil)
+			debounced.Call(2, 1, nil)
+
+			g.Assert(<-done).Equal(2)
+		})
+
+		g.It("Should return nil if Action is nil", func() {
+			g.Assert(Debounce(nil, time.Second, nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Throttle()", func() {
+		g.It("Should call the Action on both edges of wait duration", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, true, true, clock)
+
+			throttled.Call(1, 0, nil)
+			g.Assert(calls).Equal(Seq{1})
+
+			clock.Advance(10 * time.Millisecond)
+			throttled.Call(2, 1, nil)
+			clock.Advance(10 * time.Millisecond)
+			throttled.Call(3, 2, nil)
+			g.Assert(calls).Equal(Seq{1})
+
+			clock.Advance(80 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1, 3})
+
+			throttled.Call(4, 3, nil)
+			g.Assert(calls).Equal(Seq{1, 3})
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1, 3, 4})
+		})
+
+		g.It("Should skip the leading call", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, false, true, clock)
+
+			throttled.Call(1, 0, nil)
+			throttled.Call(2, 1, nil)
+			g.Assert(calls).Equal(Seq{})
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{2})
+
+			throttled.Call(3, 2, nil)
+			g.Assert(calls).Equal(Seq{2})
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{2, 3})
+		})
+
+		g.It("Should skip the trailing call", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, true, false, clock)
+
+			throttled.Call(1, 0, nil)
+			throttled.Call(2, 1, nil)
+			g.Assert(throttled.IsPending()).IsFalse()
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1})
+
+			throttled.Call(3, 2, nil)
+			g.Assert(calls).Equal(Seq{1, 3})
+		})
+
+		g.It("Should drop the postponed call and reset wait duration on Cancel", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, true, true, clock)
+
+			throttled.Call(1, 0, nil)
+			throttled.Call(2, 1, nil)
+			throttled.Cancel()
+
+			clock.Advance(50 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1})
+
+			throttled.Call(3, 2, nil)
+			g.Assert(calls).Equal(Seq{1, 3})
+		})
+
+		g.It("Should do the postponed call right away on Flush", func() {
+			clock := NewManualClock(start)
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, true, true, clock)
+
+			throttled.Call(1, 0, nil)
+			throttled.Call(2, 1, nil)
+			throttled.Flush()
+			g.Assert(calls).Equal(Seq{1, 2})
+
+			clock.Advance(time.Second)
+			g.Assert(calls).Equal(Seq{1, 2})
+		})
+
+		g.It("Should skip the stale call, which fires after the new one is scheduled", func() {
+			clock := &staleClock{ManualClock: NewManualClock(start)}
+			calls := Seq{}
+			throttled := Throttle(func(cur, _, _ Object) { calls = append(calls, cur) }, 100*time.Millisecond, true, true, clock)
+
+			throttled.Call(1, 0, nil)
+			throttled.Call(2, 1, nil)
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1, 2})
+
+			throttled.Call(3, 2, nil)
+			throttled.Cancel()
+			throttled.Call(4, 3, nil)
+			throttled.Call(5, 4, nil)
+			clock.scheduled[1]()
+
+			g.Assert(calls).Equal(Seq{1, 2, 4})
+			g.Assert(throttled.IsPending()).IsTrue()
+
+			clock.Advance(100 * time.Millisecond)
+			g.Assert(calls).Equal(Seq{1, 2, 4, 5})
+		})
+
+		g.It("Should return nil if Action is nil", func() {
+			g.Assert(Throttle(nil, time.Second, true, true, nil) == nil).IsTrue()
+		})
+	})
+}
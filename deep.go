@@ -0,0 +1,399 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DeepOptions defines the way values are compared by #DeepEqual and #DeepDiff
+type DeepOptions struct {
+	Comparators map[reflect.Type]Comparator // Comparators override comparison of values, which both have the type
+	Tolerance   float64                     // Tolerance is the absolute difference, under which floats are equal
+	IgnoreTypes bool                        // IgnoreTypes compares numbers by value, slices by elements and maps by entries, whatever their types are
+	EmptyNil    bool                        // EmptyNil makes nil slices, maps and pointers equal to empty ones
+}
+
+// DeepDifference is the single difference of two values, found by #DeepDiff
+type DeepDifference struct {
+	Path    string // Path leads to the different values in the form of #Path, so #GetPath reads them, it's empty for the values themselves
+	Left    Object // Left is the value of the first argument, nil if it's missing
+	Right   Object // Right is the value of the second argument, nil if it's missing
+	Message string // Message describes the difference
+}
+
+// String returns the path and the message of DeepDifference
+func (diff DeepDifference) String() string {
+	if diff.Path == "" {
+		return "(root): " + diff.Message
+	}
+	return diff.Path + ": " + diff.Message
+}
+
+// DeepEqual checks whether both of the values are deeply equal, it goes through nested Seq's, slices, arrays,
+// maps, pointers and all of the struct fields, NaN equals NaN, the functions are equal only if both of them are nil
+func DeepEqual(left, right Object, opts DeepOptions) bool {
+	walker := &deepWalker{opts: opts, first: true, visited: map[deepVisit]bool{}}
+	walker.compare("", reflect.ValueOf(left), reflect.ValueOf(right))
+	return len(walker.diffs) == 0
+}
+
+// DeepDiff returns all of the differences of both values (see #DeepEqual), with their paths in order of traversal,
+// map entries are ordered by their keys
+func DeepDiff(left, right Object, opts DeepOptions) []DeepDifference {
+	walker := &deepWalker{opts: opts, visited: map[deepVisit]bool{}}
+	walker.compare("", reflect.ValueOf(left), reflect.ValueOf(right))
+	return walker.diffs
+}
+
+/* private methods */
+// deepVisit is the pair of compared references, which keeps cyclic structures from endless comparison
+type deepVisit struct {
+	left, right uintptr
+	kind        reflect.Type
+}
+
+type deepWalker struct {
+	opts    DeepOptions
+	first   bool // first stops the walker after the first difference
+	diffs   []DeepDifference
+	visited map[deepVisit]bool
+}
+
+func (walker *deepWalker) done() bool {
+	return walker.first && len(walker.diffs) > 0
+}
+
+func (walker *deepWalker) report(path string, left, right reflect.Value, format string, args ...interface{}) {
+	walker.diffs = append(walker.diffs, DeepDifference{
+		Path:    path,
+		Left:    createDeepValue(left),
+		Right:   createDeepValue(right),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (walker *deepWalker) compare(path string, left, right reflect.Value) {
+	if walker.done() {
+		return
+	}
+
+	for left.IsValid() && left.Kind() == reflect.Interface {
+		left = left.Elem()
+	}
+	for right.IsValid() && right.Kind() == reflect.Interface {
+		right = right.Elem()
+	}
+
+	if !left.IsValid() || !right.IsValid() {
+		if left.IsValid() == right.IsValid() || walker.isEmpty(left) && walker.isEmpty(right) {
+			return
+		}
+		walker.report(path, left, right, "%s != %s", describeDeepValue(left), describeDeepValue(right))
+		return
+	}
+
+	if cb, ok := walker.opts.Comparators[left.Type()]; ok && left.Type() == right.Type() && left.CanInterface() && right.CanInterface() {
+		if cb(left.Interface(), right.Interface()) != equal {
+			walker.report(path, left, right, "%s != %s", describeDeepValue(left), describeDeepValue(right))
+		}
+		return
+	}
+
+	if isDeepNumber(left) && isDeepNumber(right) && (left.Type() == right.Type() || walker.opts.IgnoreTypes) {
+		if !walker.isNumberEqual(left, right) {
+			walker.report(path, left, right, "%s != %s", describeDeepValue(left), describeDeepValue(right))
+		}
+		return
+	}
+
+	if left.Type() != right.Type() && !(walker.opts.IgnoreTypes && isDeepAlike(left, right)) {
+		walker.report(path, left, right, "type %s != %s", left.Type(), right.Type())
+		return
+	}
+
+	switch left.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if walker.compareReferences(path, left, right) {
+			return
+		}
+	}
+
+	switch left.Kind() {
+	case reflect.Ptr:
+		walker.compare(path, left.Elem(), right.Elem())
+	case reflect.Slice, reflect.Array:
+		walker.compareSequences(path, left, right)
+	case reflect.Map:
+		walker.compareMaps(path, left, right)
+	case reflect.Struct:
+		for index := 0; index < left.NumField() && !walker.done(); index++ {
+			walker.compare(createDeepFieldPath(path, left.Type().Field(index).Name), left.Field(index), right.Field(index))
+		}
+	case reflect.Func:
+		if !left.IsNil() || !right.IsNil() {
+			walker.report(path, left, right, "functions are equal only if both of them are nil")
+		}
+	default:
+		if !isDeepPrimitiveEqual(left, right) {
+			walker.report(path, left, right, "%s != %s", describeDeepValue(left), describeDeepValue(right))
+		}
+	}
+}
+
+// compareReferences compares nils and the same references, returns true if nothing else should be compared
+func (walker *deepWalker) compareReferences(path string, left, right reflect.Value) bool {
+	if left.IsNil() || right.IsNil() {
+		if left.IsNil() != right.IsNil() && !(walker.isEmpty(left) && walker.isEmpty(right)) {
+			walker.report(path, left, right, "%s != %s", describeDeepValue(left), describeDeepValue(right))
+		}
+		return true
+	}
+
+	if left.Kind() == reflect.Slice && left.Len() != right.Len() {
+		return false
+	}
+
+	visit := deepVisit{left: left.Pointer(), right: right.Pointer(), kind: left.Type()}
+	if visit.left == visit.right || walker.visited[visit] {
+		return true
+	}
+
+	walker.visited[visit] = true
+	return false
+}
+
+func (walker *deepWalker) compareSequences(path string, left, right reflect.Value) {
+	length := left.Len()
+	if right.Len() > length {
+		length = right.Len()
+	}
+
+	for index := 0; index < length && !walker.done(); index++ {
+		elementPath := fmt.Sprintf("%s[%d]", path, index)
+
+		switch {
+		case index >= left.Len():
+			walker.report(elementPath, reflect.Value{}, right.Index(index), "missing on the left")
+		case index >= right.Len():
+			walker.report(elementPath, left.Index(index), reflect.Value{}, "missing on the right")
+		default:
+			walker.compare(elementPath, left.Index(index), right.Index(index))
+		}
+	}
+}
+
+func (walker *deepWalker) compareMaps(path string, left, right reflect.Value) {
+	keys := left.MapKeys()
+	for _, key := range right.MapKeys() {
+		if !hasDeepKey(left, key, walker.opts.IgnoreTypes) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool { return Compare(createDeepValue(keys[i]), createDeepValue(keys[j])) < 0 })
+
+	for _, key := range keys {
+		if walker.done() {
+			return
+		}
+
+		keyPath := createDeepKeyPath(path, key)
+		leftValue, rightValue := findDeepKey(left, key, walker.opts.IgnoreTypes), findDeepKey(right, key, walker.opts.IgnoreTypes)
+
+		switch {
+		case !leftValue.IsValid():
+			walker.report(keyPath, reflect.Value{}, rightValue, "missing on the left")
+		case !rightValue.IsValid():
+			walker.report(keyPath, leftValue, reflect.Value{}, "missing on the right")
+		default:
+			walker.compare(keyPath, leftValue, rightValue)
+		}
+	}
+}
+
+// isEmpty returns true if EmptyNil is set and the value is nil or the empty slice or map
+func (walker *deepWalker) isEmpty(value reflect.Value) bool {
+	if !walker.opts.EmptyNil {
+		return false
+	}
+
+	for value.IsValid() && value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return true
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Ptr:
+		return value.IsNil()
+	}
+
+	return false
+}
+
+func (walker *deepWalker) isNumberEqual(left, right reflect.Value) bool {
+	isFloat := left.Kind() == reflect.Float32 || left.Kind() == reflect.Float64 ||
+		right.Kind() == reflect.Float32 || right.Kind() == reflect.Float64
+
+	if !isFloat {
+		return Compare(createDeepNumber(left), createDeepNumber(right)) == equal
+	}
+
+	leftFloat, _ := toFloat64(createDeepNumber(left))
+	rightFloat, _ := toFloat64(createDeepNumber(right))
+
+	if math.IsNaN(leftFloat) || math.IsNaN(rightFloat) {
+		return math.IsNaN(leftFloat) && math.IsNaN(rightFloat)
+	}
+
+	return leftFloat == rightFloat || math.Abs(leftFloat-rightFloat) <= walker.opts.Tolerance
+}
+
+func isDeepNumber(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// createDeepNumber returns the number even from unexported field
+func createDeepNumber(value reflect.Value) Object {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint()
+	}
+	return value.Float()
+}
+
+// isDeepAlike returns true if values of different types can be compared by their content
+func isDeepAlike(left, right reflect.Value) bool {
+	isSequence := func(value reflect.Value) bool {
+		return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+	}
+
+	if isSequence(left) && isSequence(right) {
+		return true
+	}
+
+	return left.Kind() == right.Kind() && left.Kind() != reflect.Struct && left.Kind() != reflect.Ptr
+}
+
+func isDeepPrimitiveEqual(left, right reflect.Value) bool {
+	switch left.Kind() {
+	case reflect.Bool:
+		return left.Bool() == right.Bool()
+	case reflect.String:
+		return left.String() == right.String()
+	case reflect.Complex64, reflect.Complex128:
+		return left.Complex() == right.Complex()
+	case reflect.Chan, reflect.UnsafePointer:
+		return left.Pointer() == right.Pointer()
+	}
+	return false
+}
+
+// hasDeepKey returns true if the map has the key (see #findDeepKey)
+func hasDeepKey(target, key reflect.Value, ignoreTypes bool) bool {
+	return findDeepKey(target, key, ignoreTypes).IsValid()
+}
+
+// findDeepKey returns the value of map by key, if the types are ignored,
+// it also looks for the key, which is equal to given one by value, but has another type
+func findDeepKey(target, key reflect.Value, ignoreTypes bool) reflect.Value {
+	if key.Type().AssignableTo(target.Type().Key()) {
+		if value := target.MapIndex(key); value.IsValid() || !ignoreTypes {
+			return value
+		}
+	}
+	if !ignoreTypes {
+		return reflect.Value{}
+	}
+
+	for _, candidate := range target.MapKeys() {
+		if Compare(createDeepValue(candidate), createDeepValue(key)) == equal {
+			return target.MapIndex(candidate)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// createDeepFieldPath returns the path to the field, the root fields go without the leading dot, so it's parsed by #ParsePath
+func createDeepFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// createDeepKeyPath returns the path to the map entry, the names go as fields, integers as indexes
+// and the rest of the keys as quoted names, which #ParsePath reads back
+func createDeepKeyPath(path string, key reflect.Value) string {
+	for key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+
+	switch key.Kind() {
+	case reflect.String:
+		if name := key.String(); queryPath.MatchString(name) && !strings.Contains(name, ".") {
+			return createDeepFieldPath(path, name)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%s[%d]", path, key.Int())
+	}
+
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(createDeepValue(key)))
+	return path + `["` + quoted + `"]`
+}
+
+// createDeepValue returns the value as Object, the values of unexported fields are returned by their fmt representation
+func createDeepValue(value reflect.Value) Object {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.CanInterface() {
+		return value.Interface()
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func describeDeepValue(value reflect.Value) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+	return fmt.Sprintf("%#v", createDeepValue(value))
+}
@@ -0,0 +1,201 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type deepPoint struct {
+	X, Y  float64
+	label string
+}
+
+type deepNode struct {
+	Value int
+	Next  *deepNode
+}
+
+func TestDeep(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#DeepEqual()", func() {
+		g.It("Should compare nested Seq's, slices and maps", func() {
+			left := Seq{1, "a", Seq{2, []int{3, 4}}, map[string]Object{"k": Seq{5}}}
+			right := Seq{1, "a", Seq{2, []int{3, 4}}, map[string]Object{"k": Seq{5}}}
+
+			g.Assert(DeepEqual(left, right, DeepOptions{})).IsTrue()
+
+			right[2].(Seq)[1].([]int)[1] = 6
+			g.Assert(DeepEqual(left, right, DeepOptions{})).IsFalse()
+		})
+
+		g.It("Should compare structs including unexported fields", func() {
+			g.Assert(DeepEqual(deepPoint{1, 2, "a"}, deepPoint{1, 2, "a"}, DeepOptions{})).IsTrue()
+			g.Assert(DeepEqual(deepPoint{1, 2, "a"}, deepPoint{1, 2, "b"}, DeepOptions{})).IsFalse()
+			g.Assert(DeepEqual(&deepPoint{1, 2, "a"}, &deepPoint{1, 2, "a"}, DeepOptions{})).IsTrue()
+		})
+
+		g.It("Should compare floats with tolerance and NaN as equal", func() {
+			a, b := 0.1, 0.2
+
+			g.Assert(DeepEqual(Seq{a + b}, Seq{0.3}, DeepOptions{})).IsFalse()
+			g.Assert(DeepEqual(Seq{a + b}, Seq{0.3}, DeepOptions{Tolerance: 1e-9})).IsTrue()
+			g.Assert(DeepEqual(math.NaN(), math.NaN(), DeepOptions{})).IsTrue()
+		})
+
+		g.It("Should ignore types of numbers and containers only if it's asked", func() {
+			g.Assert(DeepEqual(Seq{1, 2}, []int64{1, 2}, DeepOptions{})).IsFalse()
+			g.Assert(DeepEqual(Seq{1, 2}, []int64{1, 2}, DeepOptions{IgnoreTypes: true})).IsTrue()
+			g.Assert(DeepEqual(
+				map[string]Object{"a": 1}, map[string]interface{}{"a": 1.0}, DeepOptions{IgnoreTypes: true},
+			)).IsTrue()
+		})
+
+		g.It("Should compare maps with different types of keys", func() {
+			ignore := DeepOptions{IgnoreTypes: true}
+
+			g.Assert(DeepEqual(map[string]int{"1": 1}, map[int]int{1: 1}, ignore)).IsFalse()
+			g.Assert(DeepEqual(map[int]int{1: 1}, map[int64]int{1: 1}, ignore)).IsTrue()
+			g.Assert(DeepEqual(map[string]Object{"a": 1}, map[Object]Object{"a": 1}, ignore)).IsTrue()
+			g.Assert(DeepEqual(map[Object]Object{int64(1): 1}, map[Object]Object{1: 1}, ignore)).IsTrue()
+			g.Assert(DeepEqual(map[Object]Object{int64(1): 1}, map[Object]Object{1: 1}, DeepOptions{})).IsFalse()
+			g.Assert(DeepEqual(map[string]int{"1": 1}, map[int]int{1: 1}, DeepOptions{})).IsFalse()
+		})
+
+		g.It("Should treat nil and empty as equal only if it's asked", func() {
+			var empty []int
+
+			g.Assert(DeepEqual(empty, []int{}, DeepOptions{})).IsFalse()
+			g.Assert(DeepEqual(empty, []int{}, DeepOptions{EmptyNil: true})).IsTrue()
+			g.Assert(DeepEqual(nil, map[string]int{}, DeepOptions{EmptyNil: true})).IsTrue()
+			g.Assert(DeepEqual((*int)(nil), new(int), DeepOptions{EmptyNil: true})).IsFalse()
+		})
+
+		g.It("Should use comparators of the types", func() {
+			opts := DeepOptions{Comparators: map[reflect.Type]Comparator{
+				reflect.TypeOf(""): func(left, right Object) int {
+					return strings.Compare(strings.ToLower(left.(string)), strings.ToLower(right.(string)))
+				},
+			}}
+
+			g.Assert(DeepEqual(Seq{"A", Seq{"b"}}, Seq{"a", Seq{"B"}}, opts)).IsTrue()
+			g.Assert(DeepEqual(Seq{"A"}, Seq{"c"}, opts)).IsFalse()
+		})
+
+		g.It("Should stop on cyclic structures", func() {
+			left, right := &deepNode{Value: 1}, &deepNode{Value: 1}
+			left.Next, right.Next = left, right
+
+			g.Assert(DeepEqual(left, right, DeepOptions{})).IsTrue()
+		})
+	})
+
+	g.Describe("#DeepDiff()", func() {
+		g.It("Should return nothing for equal values", func() {
+			g.Assert(len(DeepDiff(Seq{1, Seq{2}}, Seq{1, Seq{2}}, DeepOptions{}))).Equal(0)
+		})
+
+		g.It("Should report paths and values of all differences", func() {
+			left := map[string]Object{"name": "a", "tags": Seq{1, 2, 3}, "point": deepPoint{1, 2, ""}, "old": 1}
+			right := map[string]Object{"name": "b", "tags": Seq{1, 5}, "point": deepPoint{1, 3, ""}, "new key": true}
+
+			diffs := DeepDiff(left, right, DeepOptions{})
+			messages := []string{}
+			for _, diff := range diffs {
+				messages = append(messages, diff.String())
+			}
+
+			g.Assert(messages).Equal([]string{
+				`name: "a" != "b"`,
+				`["new key"]: missing on the left`,
+				`old: missing on the right`,
+				`point.Y: 2 != 3`,
+				`tags[1]: 2 != 5`,
+				`tags[2]: missing on the right`,
+			})
+
+			g.Assert(diffs[0].Left).Equal("a")
+			g.Assert(diffs[0].Right).Equal("b")
+			g.Assert(diffs[1].Left == nil).IsTrue()
+			g.Assert(diffs[1].Right).Equal(true)
+		})
+
+		g.It("Should report missing keys of maps with different types of keys", func() {
+			diffs := DeepDiff(map[string]Object{"a": 1, "b": 2}, map[Object]Object{"a": 1, 3: 4}, DeepOptions{IgnoreTypes: true})
+			messages := []string{}
+			for _, diff := range diffs {
+				messages = append(messages, diff.String())
+			}
+
+			g.Assert(messages).Equal([]string{"[3]: missing on the left", "b: missing on the right"})
+			g.Assert(len(DeepDiff(map[string]Object{"a": 1}, map[Object]Object{"a": 1}, DeepOptions{}))).Equal(1)
+		})
+
+		g.It("Should report paths, which lead to the values by GetPath", func() {
+			left := Seq{
+				map[string]Object{"items": Seq{deepPoint{1, 2, ""}}, "a b": 1, `q"\`: 1, "5": 1},
+				map[int]string{1: "a"},
+				map[float64]int{1.5: 1},
+			}
+			right := Seq{
+				map[string]Object{"items": Seq{deepPoint{1, 3, ""}}, "a b": 2, `q"\`: 2, "5": 2},
+				map[int]string{1: "b"},
+				map[float64]int{1.5: 2},
+			}
+
+			diffs := DeepDiff(left, right, DeepOptions{})
+			paths := []string{}
+			for _, diff := range diffs {
+				paths = append(paths, diff.Path)
+				if diff.Path != `[2]["1.5"]` {
+					g.Assert(GetPath(left, diff.Path)).Equal(diff.Left)
+					g.Assert(GetPath(right, diff.Path)).Equal(diff.Right)
+				}
+			}
+
+			g.Assert(paths).Equal([]string{`[0]["5"]`, `[0]["a b"]`, `[0].items[0].Y`, `[0]["q\"\\"]`, `[1][1]`, `[2]["1.5"]`})
+			g.Assert(DeepDiff(deepNode{Value: 1}, deepNode{Value: 2}, DeepOptions{})[0].Path).Equal("Value")
+		})
+
+		g.It("Should report different types at the root", func() {
+			diffs := DeepDiff(1, "1", DeepOptions{})
+
+			g.Assert(len(diffs)).Equal(1)
+			g.Assert(diffs[0].String()).Equal("(root): type int != string")
+		})
+	})
+
+	g.Describe("#Chain().DeepEqual()", func() {
+		g.It("Should deeply compare the chained Seq", func() {
+			res := Chain(Seq{Seq{1}, Seq{2}}).DeepEqual(Seq{Seq{1}, Seq{2}}, DeepOptions{}).Value()
+
+			g.Assert(res).Equal(true)
+		})
+	})
+}
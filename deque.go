@@ -0,0 +1,251 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+const minDequeCapacity = 8 /** initial capacity of Deque storage */
+
+// Deque is the special struct, which pushes and pops elements
+// at both of its ends in amortized O(1) operations, the zero Deque is empty and ready to use
+type Deque struct {
+	buf  Seq // buf is the circular storage
+	head int // head is the position of the first element in buf
+	size int // size is the count of elements
+}
+
+// Ring is the special struct, which keeps the fixed count of last pushed elements,
+// when it's full, every push overwrites the oldest element,
+// the zero Ring has no capacity and keeps nothing, use #NewRing to create it
+type Ring struct {
+	buf  Seq // buf is the circular storage
+	head int // head is the position of the oldest element in buf
+	size int // size is the count of elements
+}
+
+// NewDeque creates a new Deque, filled with elements of given slice
+func NewDeque(seq Seq) *Deque {
+	capacity := minDequeCapacity
+	for capacity < len(seq) {
+		capacity <<= 1
+	}
+
+	deque := &Deque{buf: NewSeq(capacity), size: len(seq)}
+	copy(deque.buf, seq)
+
+	return deque
+}
+
+// Len returns the count of elements in Deque
+func (deque *Deque) Len() int {
+	return deque.size
+}
+
+// PushBack puts an element to the end of Deque
+func (deque *Deque) PushBack(target Object) {
+	deque.grow()
+	deque.buf[(deque.head+deque.size)%len(deque.buf)] = target
+	deque.size++
+}
+
+// PushFront puts an element to the start of Deque
+func (deque *Deque) PushFront(target Object) {
+	deque.grow()
+	deque.head = (deque.head - 1 + len(deque.buf)) % len(deque.buf)
+	deque.buf[deque.head] = target
+	deque.size++
+}
+
+// PopBack takes the last element out of Deque, returns nil if Deque is empty
+func (deque *Deque) PopBack() Object {
+	if deque.size == 0 {
+		return nil
+	}
+
+	deque.size--
+	position := (deque.head + deque.size) % len(deque.buf)
+	target := deque.buf[position]
+	deque.buf[position] = nil
+
+	return target
+}
+
+// PopFront takes the first element out of Deque, returns nil if Deque is empty
+func (deque *Deque) PopFront() Object {
+	if deque.size == 0 {
+		return nil
+	}
+
+	target := deque.buf[deque.head]
+	deque.buf[deque.head] = nil
+	deque.head = (deque.head + 1) % len(deque.buf)
+	deque.size--
+
+	return target
+}
+
+// Front returns the first element without taking it out, returns nil if Deque is empty
+func (deque *Deque) Front() Object {
+	return deque.At(0)
+}
+
+// Back returns the last element without taking it out, returns nil if Deque is empty
+func (deque *Deque) Back() Object {
+	return deque.At(deque.size - 1)
+}
+
+// At returns the element on given position from the start, or nil if position is out of bounds
+func (deque *Deque) At(position int) Object {
+	if position < 0 || position >= deque.size {
+		return nil
+	}
+	return deque.buf[(deque.head+position)%len(deque.buf)]
+}
+
+// Each calls cb Action on each element from the start of Deque
+func (deque *Deque) Each(cb Action) {
+	if cb == nil {
+		return
+	}
+	for i := 0; i < deque.size; i++ {
+		cb(deque.buf[(deque.head+i)%len(deque.buf)], i, deque)
+	}
+}
+
+// Seq returns the slice of Deque elements from the start
+func (deque *Deque) Seq() Seq {
+	return createCircularCopy(deque.buf, deque.head, deque.size)
+}
+
+// NewRing creates a new empty Ring, which keeps up to given count of elements,
+// returns nil if capacity is not positive
+func NewRing(capacity int) *Ring {
+	if capacity <= 0 {
+		return nil
+	}
+	return &Ring{buf: NewSeq(capacity)}
+}
+
+// Len returns the count of elements in Ring
+func (ring *Ring) Len() int {
+	return ring.size
+}
+
+// Cap returns the count of elements Ring is able to keep
+func (ring *Ring) Cap() int {
+	return len(ring.buf)
+}
+
+// IsFull returns true if the next push overwrites the oldest element
+func (ring *Ring) IsFull() bool {
+	return ring.size == len(ring.buf)
+}
+
+// Push puts an element into Ring, returns overwritten oldest element
+// and true if Ring has been full, Ring without capacity returns the pushed element itself
+func (ring *Ring) Push(target Object) (Object, bool) {
+	if len(ring.buf) == 0 {
+		return target, true
+	}
+	if ring.IsFull() {
+		oldest := ring.buf[ring.head]
+		ring.buf[ring.head] = target
+		ring.head = (ring.head + 1) % len(ring.buf)
+		return oldest, true
+	}
+
+	ring.buf[(ring.head+ring.size)%len(ring.buf)] = target
+	ring.size++
+
+	return nil, false
+}
+
+// Pop takes the oldest element out of Ring, returns nil if Ring is empty
+func (ring *Ring) Pop() Object {
+	if ring.size == 0 {
+		return nil
+	}
+
+	oldest := ring.buf[ring.head]
+	ring.buf[ring.head] = nil
+	ring.head = (ring.head + 1) % len(ring.buf)
+	ring.size--
+
+	return oldest
+}
+
+// At returns the element on given position from the oldest one, or nil if position is out of bounds
+func (ring *Ring) At(position int) Object {
+	if position < 0 || position >= ring.size {
+		return nil
+	}
+	return ring.buf[(ring.head+position)%len(ring.buf)]
+}
+
+// Each calls cb Action on each element from the oldest one
+func (ring *Ring) Each(cb Action) {
+	if cb == nil {
+		return
+	}
+	for i := 0; i < ring.size; i++ {
+		cb(ring.buf[(ring.head+i)%len(ring.buf)], i, ring)
+	}
+}
+
+// Seq returns the slice of Ring elements from the oldest one
+func (ring *Ring) Seq() Seq {
+	return createCircularCopy(ring.buf, ring.head, ring.size)
+}
+
+/* private methods */
+// grow doubles the storage of Deque if it's full, the zero Deque gets the initial storage
+func (deque *Deque) grow() {
+	if deque.size < len(deque.buf) {
+		return
+	}
+	if len(deque.buf) == 0 {
+		deque.buf = NewSeq(minDequeCapacity)
+		deque.head = 0
+		return
+	}
+
+	buf := NewSeq(len(deque.buf) << 1)
+	copied := copy(buf, deque.buf[deque.head:])
+	copy(buf[copied:], deque.buf[:deque.head])
+
+	deque.buf = buf
+	deque.head = 0
+}
+
+// createCircularCopy returns the slice of size elements of circular storage, starting from head
+func createCircularCopy(buf Seq, head, size int) Seq {
+	result := NewSeq(size)
+	if size == 0 {
+		return result
+	}
+
+	copied := copy(result, buf[head:])
+	if copied < size {
+		copy(result[copied:], buf[:size-copied])
+	}
+
+	return result
+}
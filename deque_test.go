@@ -0,0 +1,212 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#NewDeque()", func() {
+		g.It("Should create Deque from the copy of Seq", func() {
+			inSeq := Seq{1, 2, 3}
+			deque := NewDeque(inSeq)
+			inSeq[0] = 100
+
+			g.Assert(deque.Len()).Equal(3)
+			g.Assert(deque.Seq()).Equal(Seq{1, 2, 3})
+			g.Assert(NewDeque(nil).Seq()).Equal(Seq{})
+			g.Assert(NewDeque(nil).Len()).Equal(0)
+		})
+	})
+
+	g.Describe("#PushBack() and #PushFront()", func() {
+		g.It("Should put elements to both ends", func() {
+			deque := NewDeque(nil)
+
+			for i := 0; i < 20; i++ {
+				deque.PushBack(i)
+				deque.PushFront(-i - 1)
+			}
+
+			g.Assert(deque.Len()).Equal(40)
+			g.Assert(deque.Front()).Equal(-20)
+			g.Assert(deque.Back()).Equal(19)
+			g.Assert(deque.At(19)).Equal(-1)
+			g.Assert(deque.At(20)).Equal(0)
+			g.Assert(deque.At(40)).Equal(nil)
+			g.Assert(deque.At(-1)).Equal(nil)
+		})
+		g.It("Should work with the zero Deque", func() {
+			var deque Deque
+
+			deque.PushBack(1)
+			deque.PushFront(0)
+
+			g.Assert(deque.Seq()).Equal(Seq{0, 1})
+			g.Assert(deque.PopBack()).Equal(1)
+
+			var empty Deque
+			g.Assert(empty.PopFront()).Equal(nil)
+			g.Assert(empty.Seq()).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#PopBack() and #PopFront()", func() {
+		g.It("Should take elements from both ends", func() {
+			deque := NewDeque(Seq{1, 2, 3, 4})
+
+			g.Assert(deque.PopFront()).Equal(1)
+			g.Assert(deque.PopBack()).Equal(4)
+			deque.PushBack(5)
+			deque.PushFront(0)
+			g.Assert(deque.Seq()).Equal(Seq{0, 2, 3, 5})
+			g.Assert(deque.PopBack()).Equal(5)
+			g.Assert(deque.PopBack()).Equal(3)
+			g.Assert(deque.PopFront()).Equal(0)
+			g.Assert(deque.PopFront()).Equal(2)
+			g.Assert(deque.PopFront()).Equal(nil)
+			g.Assert(deque.PopBack()).Equal(nil)
+			g.Assert(deque.Front()).Equal(nil)
+			g.Assert(deque.Back()).Equal(nil)
+		})
+
+		g.It("Should work as a queue through the wrapping storage", func() {
+			deque := NewDeque(nil)
+			result := NewSeq(0)
+
+			for i := 0; i < 100; i++ {
+				deque.PushBack(i)
+				if i%3 == 0 {
+					result = append(result, deque.PopFront())
+				}
+			}
+			for deque.Len() != 0 {
+				result = append(result, deque.PopFront())
+			}
+
+			expected := NewSeq(100)
+			for i := range expected {
+				expected[i] = i
+			}
+
+			g.Assert(result).Equal(expected)
+		})
+	})
+
+	g.Describe("#Each()", func() {
+		g.It("Should call Action on each element from the start", func() {
+			deque := NewDeque(Seq{2, 3})
+			deque.PushFront(1)
+			result := NewSeq(0)
+
+			deque.Each(func(cur, index, _ Object) { result = append(result, cur.(int)*10+index.(int)) })
+			deque.Each(nil)
+
+			g.Assert(result).Equal(Seq{10, 21, 32})
+		})
+	})
+}
+
+func TestRing(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#NewRing()", func() {
+		g.It("Should create empty Ring of given capacity", func() {
+			ring := NewRing(3)
+
+			g.Assert(ring.Len()).Equal(0)
+			g.Assert(ring.Cap()).Equal(3)
+			g.Assert(ring.IsFull()).IsFalse()
+			g.Assert(ring.Seq()).Equal(Seq{})
+			g.Assert(NewRing(0) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Push()", func() {
+		g.It("Should overwrite the oldest element when Ring is full", func() {
+			ring := NewRing(3)
+
+			for i := 1; i <= 3; i++ {
+				evicted, full := ring.Push(i)
+				g.Assert(evicted).Equal(nil)
+				g.Assert(full).IsFalse()
+			}
+
+			evicted, full := ring.Push(4)
+
+			g.Assert(evicted).Equal(1)
+			g.Assert(full).IsTrue()
+			g.Assert(ring.IsFull()).IsTrue()
+			g.Assert(ring.Seq()).Equal(Seq{2, 3, 4})
+			g.Assert(ring.At(0)).Equal(2)
+			g.Assert(ring.At(3)).Equal(nil)
+		})
+		g.It("Should keep nothing in the zero Ring", func() {
+			var ring Ring
+
+			evicted, full := ring.Push(1)
+
+			g.Assert(evicted).Equal(1)
+			g.Assert(full).IsTrue()
+			g.Assert(ring.Len()).Equal(0)
+			g.Assert(ring.Pop()).Equal(nil)
+			g.Assert(ring.Seq()).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Pop()", func() {
+		g.It("Should take the oldest element", func() {
+			ring := NewRing(2)
+			ring.Push(1)
+			ring.Push(2)
+			ring.Push(3)
+
+			g.Assert(ring.Pop()).Equal(2)
+			ring.Push(4)
+			g.Assert(ring.Pop()).Equal(3)
+			g.Assert(ring.Pop()).Equal(4)
+			g.Assert(ring.Pop()).Equal(nil)
+			g.Assert(ring.Len()).Equal(0)
+		})
+	})
+
+	g.Describe("#Each()", func() {
+		g.It("Should call Action on each element from the oldest one", func() {
+			ring := NewRing(2)
+			ring.Push("a")
+			ring.Push("b")
+			ring.Push("c")
+			result := NewSeq(0)
+
+			ring.Each(func(cur, _, _ Object) { result = append(result, cur) })
+			ring.Each(nil)
+
+			g.Assert(result).Equal(Seq{"b", "c"})
+		})
+	})
+}
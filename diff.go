@@ -0,0 +1,380 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// EditOp is the kind of the Edit
+type EditOp int
+
+// Kinds of the edits in the script returned by #Diff
+const (
+	EditKeep     EditOp = iota // EditKeep keeps the value, which is present in both of the Seq's
+	EditDelete                 // EditDelete removes the value of the left Seq
+	EditInsert                 // EditInsert adds the value of the right Seq
+	EditMoveFrom               // EditMoveFrom removes the value, which is inserted somewhere else by EditMoveTo
+	EditMoveTo                 // EditMoveTo adds the value, which is removed somewhere else by EditMoveFrom
+)
+
+// Edit is the single step of the edit script, OldIndex is -1 for the inserted values and NewIndex is -1 for the
+// removed ones, the moved values have both of the indices pointing at the position in the old and in the new Seq
+type Edit struct {
+	Op       EditOp
+	OldIndex int
+	NewIndex int
+	Value    Object
+}
+
+// String returns the name of EditOp
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "keep"
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	case EditMoveFrom:
+		return "move from"
+	case EditMoveTo:
+		return "move to"
+	}
+	return fmt.Sprintf("EditOp(%d)", int(op))
+}
+
+// String returns the operation, indices and value of Edit
+func (edit Edit) String() string {
+	return fmt.Sprintf("%s %d %d %v", edit.Op, edit.OldIndex, edit.NewIndex, edit.Value)
+}
+
+// Diff returns the shortest edit script, which turns the left (old) Seq into the right (new) one,
+// it uses Myers O(ND) algorithm in linear space, removed and inserted values,
+// which are equal by Comparator, are reported as moved
+func Diff(left, right Seq, cb Comparator) []Edit {
+	if cb == nil {
+		return []Edit{}
+	}
+
+	result := createMyersEdits(left, right, cb)
+	createMoves(result, cb)
+	return result
+}
+
+// Patch applies the edit script returned by #Diff to the Seq, the script must cover all of the values of the Seq,
+// the error is returned when the script doesn't fit the Seq
+func Patch(seq Seq, edits []Edit) (Seq, error) {
+	result := NewSeq(0)
+	position := 0
+
+	for index, edit := range edits {
+		switch edit.Op {
+		case EditKeep, EditDelete, EditMoveFrom:
+			if edit.OldIndex != position || position >= len(seq) {
+				return nil, fmt.Errorf("ugo: edit %d expects old index %d, but the position is %d of %d",
+					index, edit.OldIndex, position, len(seq))
+			}
+			if edit.Op == EditKeep {
+				result = append(result, seq[position])
+			}
+			position++
+		case EditInsert, EditMoveTo:
+			result = append(result, edit.Value)
+		default:
+			return nil, fmt.Errorf("ugo: edit %d has unknown operation %s", index, edit.Op)
+		}
+	}
+
+	if position != len(seq) {
+		return nil, fmt.Errorf("ugo: edit script covers %d of %d values", position, len(seq))
+	}
+
+	return result, nil
+}
+
+// Unified renders the edit script as unified diff hunks with the given number of unchanged values around the changes,
+// every value is the single line, which starts with " ", "-" or "+"
+func Unified(edits []Edit, context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	oldPositions, newPositions := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for index, edit := range edits {
+		oldPositions[index+1], newPositions[index+1] = oldPositions[index], newPositions[index]
+		if edit.Op != EditInsert && edit.Op != EditMoveTo {
+			oldPositions[index+1]++
+		}
+		if edit.Op != EditDelete && edit.Op != EditMoveFrom {
+			newPositions[index+1]++
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	for start := 0; start < len(edits); {
+		if edits[start].Op == EditKeep {
+			start++
+			continue
+		}
+
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+
+		end, unchanged := start, 0
+		for end < len(edits) && unchanged <= 2*context {
+			if edits[end].Op == EditKeep {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			end++
+		}
+		if unchanged > context {
+			end -= unchanged - context
+		}
+
+		oldStart, oldLength := oldPositions[first], oldPositions[end]-oldPositions[first]
+		newStart, newLength := newPositions[first], newPositions[end]-newPositions[first]
+		if oldLength > 0 {
+			oldStart++
+		}
+		if newLength > 0 {
+			newStart++
+		}
+
+		fmt.Fprintf(&buffer, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLength, newStart, newLength)
+		for _, edit := range edits[first:end] {
+			fmt.Fprintf(&buffer, "%s%v\n", createUnifiedPrefix(edit.Op), edit.Value)
+		}
+
+		start = end
+	}
+
+	return buffer.String()
+}
+
+/* private methods */
+// myersDiff keeps the state of Myers algorithm, which finds the middle snake of the shortest path
+// and divides both Seq's by it, so it needs the memory only for the furthest reaching paths of both directions
+type myersDiff struct {
+	left, right       Seq
+	cb                Comparator
+	forward, backward []int // forward and backward keep the furthest x of every diagonal, backward counts from the end
+	edits             []Edit
+}
+
+// createMyersEdits returns the shortest edit script in order of both Seq's, it deletes before inserting
+func createMyersEdits(left, right Seq, cb Comparator) []Edit {
+	size := len(left) + len(right) + 3
+	diff := &myersDiff{
+		left: left, right: right, cb: cb,
+		forward: make([]int, size), backward: make([]int, size),
+		edits: make([]Edit, 0, len(left)+len(right)),
+	}
+
+	diff.compare(0, len(left), 0, len(right))
+	diff.orderChanges()
+	return diff.edits
+}
+
+// compare appends the edits, which turn left[oldLow:oldHigh] into right[newLow:newHigh]
+func (diff *myersDiff) compare(oldLow, oldHigh, newLow, newHigh int) {
+	for oldLow < oldHigh && newLow < newHigh && diff.isEqual(oldLow, newLow) {
+		diff.keep(oldLow, newLow)
+		oldLow, newLow = oldLow+1, newLow+1
+	}
+
+	suffix := 0
+	for oldLow < oldHigh-suffix && newLow < newHigh-suffix && diff.isEqual(oldHigh-suffix-1, newHigh-suffix-1) {
+		suffix++
+	}
+	oldHigh, newHigh = oldHigh-suffix, newHigh-suffix
+
+	switch {
+	case oldLow == oldHigh:
+		for ; newLow < newHigh; newLow++ {
+			diff.edits = append(diff.edits, Edit{Op: EditInsert, OldIndex: -1, NewIndex: newLow, Value: diff.right[newLow]})
+		}
+	case newLow == newHigh:
+		for ; oldLow < oldHigh; oldLow++ {
+			diff.edits = append(diff.edits, Edit{Op: EditDelete, OldIndex: oldLow, NewIndex: -1, Value: diff.left[oldLow]})
+		}
+	default:
+		// both parts aren't empty and differ at both ends, so the script has at least 2 edits
+		// and each of the halves around the middle snake is smaller than the whole part
+		x, y, u, v := diff.findMiddleSnake(oldLow, oldHigh, newLow, newHigh)
+		diff.compare(oldLow, x, newLow, y)
+		for ; x < u; x, y = x+1, y+1 {
+			diff.keep(x, y)
+		}
+		diff.compare(u, oldHigh, v, newHigh)
+	}
+
+	for index := 0; index < suffix; index++ {
+		diff.keep(oldHigh+index, newHigh+index)
+	}
+}
+
+// findMiddleSnake runs the search from both ends of the parts until the paths overlap,
+// returns the start (x, y) and the end (u, v) of the snake, where they meet
+func (diff *myersDiff) findMiddleSnake(oldLow, oldHigh, newLow, newHigh int) (x, y, u, v int) {
+	lengthOld, lengthNew := oldHigh-oldLow, newHigh-newLow
+	delta := lengthOld - lengthNew
+	odd := delta%2 != 0
+	limit := (lengthOld + lengthNew + 1) / 2
+	offset := limit + 1
+	forward, backward := diff.forward, diff.backward
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	for step := 0; step <= limit; step++ {
+		for diagonal := -step; diagonal <= step; diagonal += 2 {
+			x := forward[offset+diagonal+1]
+			if diagonal != -step && (diagonal == step || forward[offset+diagonal-1] >= x) {
+				x = forward[offset+diagonal-1] + 1
+			}
+
+			y := x - diagonal
+			startX, startY := x, y
+			for x < lengthOld && y < lengthNew && diff.isEqual(oldLow+x, newLow+y) {
+				x, y = x+1, y+1
+			}
+			forward[offset+diagonal] = x
+
+			// the backward paths of the previous step lie on the diagonal delta - diagonal
+			if reverse := delta - diagonal; odd && reverse >= 1-step && reverse <= step-1 && x+backward[offset+reverse] >= lengthOld {
+				return oldLow + startX, newLow + startY, oldLow + x, newLow + y
+			}
+		}
+
+		for diagonal := -step; diagonal <= step; diagonal += 2 {
+			x := backward[offset+diagonal+1]
+			if diagonal != -step && (diagonal == step || backward[offset+diagonal-1] >= x) {
+				x = backward[offset+diagonal-1] + 1
+			}
+
+			y := x - diagonal
+			startX, startY := x, y
+			for x < lengthOld && y < lengthNew && diff.isEqual(oldHigh-x-1, newHigh-y-1) {
+				x, y = x+1, y+1
+			}
+			backward[offset+diagonal] = x
+
+			if reverse := delta - diagonal; !odd && reverse >= -step && reverse <= step && x+forward[offset+reverse] >= lengthOld {
+				return oldHigh - x, newHigh - y, oldHigh - startX, newHigh - startY
+			}
+		}
+	}
+
+	// the paths always meet within the half of the longest script
+	panic("ugo: middle snake is not found")
+}
+
+// orderChanges puts the deletions of every run of changes before the insertions, keeping their order
+func (diff *myersDiff) orderChanges() {
+	inserted := []Edit{}
+
+	for start := 0; start < len(diff.edits); start++ {
+		if diff.edits[start].Op == EditKeep {
+			continue
+		}
+
+		end, position := start, start
+		inserted = inserted[:0]
+		for ; end < len(diff.edits) && diff.edits[end].Op != EditKeep; end++ {
+			if diff.edits[end].Op == EditInsert {
+				inserted = append(inserted, diff.edits[end])
+			} else {
+				diff.edits[position] = diff.edits[end]
+				position++
+			}
+		}
+
+		copy(diff.edits[position:end], inserted)
+		start = end
+	}
+}
+
+func (diff *myersDiff) isEqual(oldIndex, newIndex int) bool {
+	return diff.cb(diff.left[oldIndex], diff.right[newIndex]) == equal
+}
+
+func (diff *myersDiff) keep(oldIndex, newIndex int) {
+	diff.edits = append(diff.edits, Edit{Op: EditKeep, OldIndex: oldIndex, NewIndex: newIndex, Value: diff.left[oldIndex]})
+}
+
+// createMoves pairs every removed value with the first unpaired inserted value, which is equal to it,
+// inserted values are sorted by Comparator, so the equal ones are found by binary search
+func createMoves(edits []Edit, cb Comparator) {
+	inserted := []int{}
+	for index, edit := range edits {
+		if edit.Op == EditInsert {
+			inserted = append(inserted, index)
+		}
+	}
+
+	sort.SliceStable(inserted, func(i, j int) bool { return cb(edits[inserted[i]].Value, edits[inserted[j]].Value) < 0 })
+
+	// unpaired keeps the position of the first unpaired value in the run of equal ones, by the start of the run
+	unpaired := map[int]int{}
+
+	for from := range edits {
+		if edits[from].Op != EditDelete {
+			continue
+		}
+
+		value := edits[from].Value
+		start := sort.Search(len(inserted), func(i int) bool { return cb(edits[inserted[i]].Value, value) >= 0 })
+		if start == len(inserted) || cb(edits[inserted[start]].Value, value) != equal {
+			continue
+		}
+
+		position, ok := unpaired[start]
+		if !ok {
+			position = start
+		}
+		if position == len(inserted) || cb(edits[inserted[position]].Value, value) != equal {
+			continue
+		}
+		unpaired[start] = position + 1
+
+		to := inserted[position]
+		edits[from].Op, edits[from].NewIndex = EditMoveFrom, edits[to].NewIndex
+		edits[to].Op, edits[to].OldIndex = EditMoveTo, edits[from].OldIndex
+	}
+}
+
+func createUnifiedPrefix(op EditOp) string {
+	switch op {
+	case EditDelete, EditMoveFrom:
+		return "-"
+	case EditInsert, EditMoveTo:
+		return "+"
+	}
+	return " "
+}
@@ -0,0 +1,204 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"fmt"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math/rand"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	g := Goblin(t)
+
+	describe := func(edits []Edit) []string {
+		result := []string{}
+		for _, edit := range edits {
+			result = append(result, edit.String())
+		}
+		return result
+	}
+
+	g.Describe("#Diff()", func() {
+		g.It("Should return the shortest edit script", func() {
+			edits := Diff(Seq{"a", "b", "c", "a", "b", "b", "a"}, Seq{"c", "b", "a", "b", "a", "c"}, Compare)
+			changes := 0
+
+			for _, edit := range edits {
+				if edit.Op != EditKeep {
+					changes++
+				}
+			}
+
+			g.Assert(changes).Equal(5)
+		})
+
+		g.It("Should report inserted, removed and moved values", func() {
+			edits := Diff(Seq{1, 2, 3, 4}, Seq{1, 3, 5, 4, 2}, Compare)
+
+			g.Assert(describe(edits)).Equal([]string{
+				"keep 0 0 1",
+				"move from 1 4 2",
+				"keep 2 1 3",
+				"insert -1 2 5",
+				"keep 3 3 4",
+				"move to 1 4 2",
+			})
+		})
+
+		g.It("Should delete before inserting for the completely different Seq's", func() {
+			left, right := Seq{1, 2, 3, 4}, Seq{5, 6, 7}
+			edits := Diff(left, right, Compare)
+
+			g.Assert(describe(edits)).Equal([]string{
+				"delete 0 -1 1",
+				"delete 1 -1 2",
+				"delete 2 -1 3",
+				"delete 3 -1 4",
+				"insert -1 0 5",
+				"insert -1 1 6",
+				"insert -1 2 7",
+			})
+		})
+
+		g.It("Should be as short as the longest common subsequence allows", func() {
+			rng := rand.New(rand.NewSource(1))
+
+			for round := 0; round < 200; round++ {
+				left, right := NewSeq(rng.Intn(40)), NewSeq(rng.Intn(40))
+				for index := range left {
+					left[index] = rng.Intn(5)
+				}
+				for index := range right {
+					right[index] = rng.Intn(5)
+				}
+
+				table := make([][]int, len(left)+1)
+				for x := range table {
+					table[x] = make([]int, len(right)+1)
+				}
+				for x := len(left) - 1; x >= 0; x-- {
+					for y := len(right) - 1; y >= 0; y-- {
+						if left[x] == right[y] {
+							table[x][y] = table[x+1][y+1] + 1
+						} else if table[x+1][y] > table[x][y+1] {
+							table[x][y] = table[x+1][y]
+						} else {
+							table[x][y] = table[x][y+1]
+						}
+					}
+				}
+
+				edits := Diff(left, right, Compare)
+				kept := Seq{}
+				for _, edit := range edits {
+					if edit.Op == EditKeep {
+						kept = append(kept, edit.Value)
+					}
+				}
+
+				res, err := Patch(left, edits)
+				g.Assert(err == nil).IsTrue()
+				g.Assert(res).Equal(right)
+				g.Assert(len(kept)).Equal(table[0][0])
+			}
+		})
+
+		g.It("Should pair moved values in order of their positions", func() {
+			edits := Diff(Seq{"a", "a", "x", "y", "b"}, Seq{"x", "y", "a", "b", "a"}, Compare)
+
+			g.Assert(describe(edits)).Equal([]string{
+				"move from 0 2 a",
+				"move from 1 4 a",
+				"keep 2 0 x",
+				"keep 3 1 y",
+				"move to 0 2 a",
+				"keep 4 3 b",
+				"move to 1 4 a",
+			})
+		})
+
+		g.It("Should handle empty and nil Seq's", func() {
+			g.Assert(len(Diff(nil, nil, Compare))).Equal(0)
+			g.Assert(describe(Diff(nil, Seq{1}, Compare))).Equal([]string{"insert -1 0 1"})
+			g.Assert(describe(Diff(Seq{1}, Seq{}, Compare))).Equal([]string{"delete 0 -1 1"})
+			g.Assert(len(Diff(Seq{1}, Seq{2}, nil))).Equal(0)
+		})
+	})
+
+	g.Describe("#Patch()", func() {
+		g.It("Should turn the old Seq into the new one", func() {
+			pairs := [][2]Seq{
+				{Seq{"a", "b", "c", "a", "b", "b", "a"}, Seq{"c", "b", "a", "b", "a", "c"}},
+				{Seq{1, 2, 3, 4}, Seq{1, 3, 5, 4, 2}},
+				{Seq{1, 2, 3}, Seq{4, 5, 6, 7}},
+				{Seq{}, Seq{1, 2}},
+				{Seq{1, 2}, Seq{}},
+			}
+
+			for _, pair := range pairs {
+				res, err := Patch(pair[0], Diff(pair[0], pair[1], Compare))
+
+				g.Assert(err == nil).IsTrue()
+				g.Assert(res).Equal(pair[1])
+			}
+		})
+
+		g.It("Should return error if the script doesn't fit the Seq", func() {
+			edits := Diff(Seq{1, 2, 3}, Seq{1, 3}, Compare)
+
+			_, err := Patch(Seq{1, 2}, edits)
+			g.Assert(err.Error()).Equal("ugo: edit 2 expects old index 2, but the position is 2 of 2")
+
+			_, err = Patch(Seq{1, 2, 3, 4}, edits)
+			g.Assert(err.Error()).Equal("ugo: edit script covers 3 of 4 values")
+		})
+	})
+
+	g.Describe("#Unified()", func() {
+		g.It("Should render the changes with context", func() {
+			left := Seq{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+			right := Seq{1, 2, 30, 4, 5, 6, 7, 8, 9, 10, 11}
+
+			g.Assert(Unified(Diff(left, right, Compare), 1)).Equal(
+				"@@ -2,3 +2,3 @@\n 2\n-3\n+30\n 4\n" +
+					"@@ -10,1 +10,2 @@\n 10\n+11\n",
+			)
+			g.Assert(Unified(Diff(left, right, Compare), 4)).Equal(
+				"@@ -1,10 +1,11 @@\n 1\n 2\n-3\n+30\n 4\n 5\n 6\n 7\n 8\n 9\n 10\n+11\n",
+			)
+			g.Assert(Unified(Diff(Seq{}, Seq{1}, Compare), 3)).Equal("@@ -0,0 +1,1 @@\n+1\n")
+			g.Assert(Unified(Diff(left, left, Compare), 3)).Equal("")
+		})
+	})
+
+	g.Describe("#Chain().Diff()", func() {
+		g.It("Should return the edit script of the chained Seq", func() {
+			res := Chain(Seq{1, 2}).Diff(Seq{2, 1}, Compare).Value()
+
+			g.Assert(fmt.Sprint(describe(res.([]Edit)))).Equal("[move from 0 1 1 keep 1 0 2 move to 0 1 1]")
+		})
+	})
+}
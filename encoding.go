@@ -0,0 +1,525 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// binaryMagic is the header of the binary representation, its last byte is the version of the format
+var binaryMagic = []byte{'u', 'g', 'o', 1}
+
+// binary type tags of the elements
+const (
+	binaryNil byte = iota
+	binaryFalse
+	binaryTrue
+	binaryInt
+	binaryInt8
+	binaryInt16
+	binaryInt32
+	binaryInt64
+	binaryUint
+	binaryUint8
+	binaryUint16
+	binaryUint32
+	binaryUint64
+	binaryFloat32
+	binaryFloat64
+	binaryString
+	binaryBytes
+	binarySeq
+	binarySlice
+	binaryObjectMap
+	binaryInterfaceMap
+	binaryGroupMap
+)
+
+// gobRegistered keeps the types, which have been registered by #RegisterGob
+var gobRegistered sync.Map
+
+func init() {
+	registerGobType(reflect.TypeOf(Seq{}))
+	registerGobType(reflect.TypeOf(map[Object]Seq{}))
+	registerGobType(reflect.TypeOf(map[string]Object{}))
+	registerGobType(reflect.TypeOf(map[string]interface{}{}))
+	registerGobType(reflect.TypeOf([]interface{}{}))
+}
+
+// RegisterGob registers concrete types of given values and all of their nested elements in encoding/gob,
+// so they can be sent as elements of Seq, it should be called on both encoding and decoding side
+// NOTE: gob sends pointers as the values they point to, so pointer elements are decoded as values,
+// the types should not be registered as pointers via gob.Register directly, or gob panics on the duplicate
+func RegisterGob(values ...Object) {
+	for _, value := range values {
+		createGobRegistration(value)
+	}
+}
+
+// EncodeGob returns gob representation of the value, registering its element types first (see #RegisterGob)
+func EncodeGob(value Object) ([]byte, error) {
+	RegisterGob(value)
+
+	var buf bytes.Buffer
+	wrapped := interface{}(value)
+
+	if err := gob.NewEncoder(&buf).Encode(&wrapped); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeGob returns the value, decoded from gob representation,
+// types of the elements should be registered beforehand (see #RegisterGob)
+func DecodeGob(data []byte) (Object, error) {
+	var value interface{}
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// EncodeBinary returns compact self-describing binary representation of the value,
+// it supports nil, bool, numbers, strings, []byte, Seq, []interface{},
+// map[string]Object, map[string]interface{} and map[Object]Seq (see #GroupBy) nested in any way
+func EncodeBinary(value Object) ([]byte, error) {
+	buf := append([]byte{}, binaryMagic...)
+	return createBinaryValue(buf, value)
+}
+
+// DecodeBinary returns the value, decoded from binary representation (see #EncodeBinary)
+func DecodeBinary(data []byte) (Object, error) {
+	if !bytes.HasPrefix(data, binaryMagic) {
+		return nil, errors.New("ugo: data is not in ugo binary format")
+	}
+
+	decoder := &binaryDecoder{data: data, pos: len(binaryMagic)}
+
+	value, err := decoder.value()
+	if err != nil {
+		return nil, fmt.Errorf("ugo: offset %d: %v", decoder.pos, err)
+	}
+
+	if decoder.pos != len(data) {
+		return nil, fmt.Errorf("ugo: offset %d: unexpected data after the value", decoder.pos)
+	}
+
+	return value, nil
+}
+
+/* private methods */
+// createGobRegistration registers concrete type of the value and walks through its elements
+func createGobRegistration(value Object) {
+	if value == nil {
+		return
+	}
+
+	registerGobType(reflect.TypeOf(value))
+
+	switch typed := value.(type) {
+	case Seq:
+		for _, cur := range typed {
+			createGobRegistration(cur)
+		}
+	case []interface{}:
+		for _, cur := range typed {
+			createGobRegistration(cur)
+		}
+	case map[string]Object:
+		for _, cur := range typed {
+			createGobRegistration(cur)
+		}
+	case map[string]interface{}:
+		for _, cur := range typed {
+			createGobRegistration(cur)
+		}
+	case map[Object]Seq:
+		for key, cur := range typed {
+			createGobRegistration(key)
+			createGobRegistration(cur)
+		}
+	}
+}
+
+// registerGobType registers the type by its base, since gob doesn't distinguish pointers from values,
+// every type is registered only once, so gob never sees the same type under another name
+func registerGobType(kind reflect.Type) {
+	for kind.Kind() == reflect.Ptr {
+		kind = kind.Elem()
+	}
+
+	if _, done := gobRegistered.LoadOrStore(kind, true); done {
+		return
+	}
+
+	gob.Register(reflect.Zero(kind).Interface())
+}
+
+func createBinaryValue(buf []byte, value Object) ([]byte, error) {
+	switch typed := value.(type) {
+	case nil:
+		return append(buf, binaryNil), nil
+	case bool:
+		if typed {
+			return append(buf, binaryTrue), nil
+		}
+		return append(buf, binaryFalse), nil
+	case int:
+		return binary.AppendVarint(append(buf, binaryInt), int64(typed)), nil
+	case int8:
+		return append(buf, binaryInt8, byte(typed)), nil
+	case int16:
+		return binary.AppendVarint(append(buf, binaryInt16), int64(typed)), nil
+	case int32:
+		return binary.AppendVarint(append(buf, binaryInt32), int64(typed)), nil
+	case int64:
+		return binary.AppendVarint(append(buf, binaryInt64), typed), nil
+	case uint:
+		return binary.AppendUvarint(append(buf, binaryUint), uint64(typed)), nil
+	case uint8:
+		return append(buf, binaryUint8, typed), nil
+	case uint16:
+		return binary.AppendUvarint(append(buf, binaryUint16), uint64(typed)), nil
+	case uint32:
+		return binary.AppendUvarint(append(buf, binaryUint32), uint64(typed)), nil
+	case uint64:
+		return binary.AppendUvarint(append(buf, binaryUint64), typed), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(buf, binaryFloat32), math.Float32bits(typed)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(buf, binaryFloat64), math.Float64bits(typed)), nil
+	case string:
+		return append(binary.AppendUvarint(append(buf, binaryString), uint64(len(typed))), typed...), nil
+	case []byte:
+		return append(binary.AppendUvarint(append(buf, binaryBytes), uint64(len(typed))), typed...), nil
+	case Seq:
+		return createBinarySeq(append(buf, binarySeq), typed)
+	case []interface{}:
+		return createBinarySeq(append(buf, binarySlice), typed)
+	case map[string]Object:
+		return createBinaryMap(append(buf, binaryObjectMap), len(typed), func(yield func(key string, cur Object) error) error {
+			for key, cur := range typed {
+				if err := yield(key, cur); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case map[string]interface{}:
+		return createBinaryMap(append(buf, binaryInterfaceMap), len(typed), func(yield func(key string, cur Object) error) error {
+			for key, cur := range typed {
+				if err := yield(key, cur); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case map[Object]Seq:
+		var err error
+		buf = binary.AppendUvarint(append(buf, binaryGroupMap), uint64(len(typed)))
+
+		for key, cur := range typed {
+			if buf, err = createBinaryValue(buf, key); err != nil {
+				return nil, err
+			}
+			if buf, err = createBinarySeq(buf, cur); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	}
+
+	return nil, fmt.Errorf("ugo: type %T is not supported by binary codec", value)
+}
+
+func createBinarySeq(buf []byte, seq Seq) ([]byte, error) {
+	var err error
+	buf = binary.AppendUvarint(buf, uint64(len(seq)))
+
+	for _, cur := range seq {
+		if buf, err = createBinaryValue(buf, cur); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func createBinaryMap(buf []byte, length int, each func(yield func(key string, cur Object) error) error) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(length))
+
+	err := each(func(key string, cur Object) error {
+		var err error
+		buf = append(binary.AppendUvarint(buf, uint64(len(key))), key...)
+		buf, err = createBinaryValue(buf, cur)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// binaryDecoder reads values from binary representation one by one
+type binaryDecoder struct {
+	data []byte
+	pos  int
+}
+
+var errBinaryTruncated = errors.New("unexpected end of data")
+
+func (decoder *binaryDecoder) value() (Object, error) {
+	tag, err := decoder.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case binaryNil:
+		return nil, nil
+	case binaryFalse:
+		return false, nil
+	case binaryTrue:
+		return true, nil
+	case binaryInt:
+		value, err := decoder.varint(strconv.IntSize)
+		return int(value), err
+	case binaryInt8:
+		value, err := decoder.byte()
+		return int8(value), err
+	case binaryInt16:
+		value, err := decoder.varint(16)
+		return int16(value), err
+	case binaryInt32:
+		value, err := decoder.varint(32)
+		return int32(value), err
+	case binaryInt64:
+		return decoder.varint(64)
+	case binaryUint:
+		value, err := decoder.uvarint(strconv.IntSize)
+		return uint(value), err
+	case binaryUint8:
+		return decoder.byte()
+	case binaryUint16:
+		value, err := decoder.uvarint(16)
+		return uint16(value), err
+	case binaryUint32:
+		value, err := decoder.uvarint(32)
+		return uint32(value), err
+	case binaryUint64:
+		return decoder.uvarint(64)
+	case binaryFloat32:
+		raw, err := decoder.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(raw)), nil
+	case binaryFloat64:
+		raw, err := decoder.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(raw)), nil
+	case binaryString:
+		return decoder.string()
+	case binaryBytes:
+		raw, err := decoder.sized()
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, raw...), nil
+	case binarySeq:
+		return decoder.seq()
+	case binarySlice:
+		seq, err := decoder.seq()
+		return []interface{}(seq), err
+	case binaryObjectMap:
+		result := map[string]Object{}
+		err := decoder.pairs(func(key string, cur Object) { result[key] = cur })
+		return result, err
+	case binaryInterfaceMap:
+		result := map[string]interface{}{}
+		err := decoder.pairs(func(key string, cur Object) { result[key] = cur })
+		return result, err
+	case binaryGroupMap:
+		return decoder.groups()
+	}
+
+	decoder.pos--
+	return nil, fmt.Errorf("unknown type tag %d", tag)
+}
+
+func (decoder *binaryDecoder) byte() (byte, error) {
+	if decoder.pos >= len(decoder.data) {
+		return 0, errBinaryTruncated
+	}
+
+	decoder.pos++
+	return decoder.data[decoder.pos-1], nil
+}
+
+func (decoder *binaryDecoder) bytes(length int) ([]byte, error) {
+	if length < 0 || length > len(decoder.data)-decoder.pos {
+		return nil, errBinaryTruncated
+	}
+
+	decoder.pos += length
+	return decoder.data[decoder.pos-length : decoder.pos], nil
+}
+
+func (decoder *binaryDecoder) varint(bits uint) (int64, error) {
+	value, read := binary.Varint(decoder.data[decoder.pos:])
+	if read <= 0 {
+		return 0, errBinaryTruncated
+	}
+
+	if bits < 64 && (value < -1<<(bits-1) || value >= 1<<(bits-1)) {
+		return 0, fmt.Errorf("value %d overflows %d bits", value, bits)
+	}
+
+	decoder.pos += read
+	return value, nil
+}
+
+func (decoder *binaryDecoder) uvarint(bits uint) (uint64, error) {
+	value, read := binary.Uvarint(decoder.data[decoder.pos:])
+	if read <= 0 {
+		return 0, errBinaryTruncated
+	}
+
+	if bits < 64 && value >= 1<<bits {
+		return 0, fmt.Errorf("value %d overflows %d bits", value, bits)
+	}
+
+	decoder.pos += read
+	return value, nil
+}
+
+// length reads the count of items, each of them takes at least one byte, so it can't exceed the rest of data
+func (decoder *binaryDecoder) length() (int, error) {
+	length, err := decoder.uvarint(64)
+	if err != nil {
+		return 0, err
+	}
+
+	if length > uint64(len(decoder.data)-decoder.pos) {
+		return 0, errBinaryTruncated
+	}
+
+	return int(length), nil
+}
+
+func (decoder *binaryDecoder) sized() ([]byte, error) {
+	length, err := decoder.length()
+	if err != nil {
+		return nil, err
+	}
+	return decoder.bytes(length)
+}
+
+func (decoder *binaryDecoder) string() (string, error) {
+	raw, err := decoder.sized()
+	return string(raw), err
+}
+
+func (decoder *binaryDecoder) seq() (Seq, error) {
+	length, err := decoder.length()
+	if err != nil {
+		return nil, err
+	}
+
+	seq := make(Seq, length)
+	for index := range seq {
+		if seq[index], err = decoder.value(); err != nil {
+			return nil, err
+		}
+	}
+
+	return seq, nil
+}
+
+func (decoder *binaryDecoder) pairs(cb func(key string, cur Object)) error {
+	length, err := decoder.length()
+	if err != nil {
+		return err
+	}
+
+	for index := 0; index < length; index++ {
+		key, err := decoder.string()
+		if err != nil {
+			return err
+		}
+
+		cur, err := decoder.value()
+		if err != nil {
+			return err
+		}
+
+		cb(key, cur)
+	}
+
+	return nil
+}
+
+func (decoder *binaryDecoder) groups() (map[Object]Seq, error) {
+	length, err := decoder.length()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[Object]Seq, length)
+
+	for index := 0; index < length; index++ {
+		key, err := decoder.value()
+		if err != nil {
+			return nil, err
+		}
+
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("group key of type %T is not comparable", key)
+		}
+
+		seq, err := decoder.seq()
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = seq
+	}
+
+	return result, nil
+}
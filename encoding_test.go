@@ -0,0 +1,181 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math"
+	"testing"
+)
+
+type gobPoint struct {
+	X, Y int
+}
+
+func TestEncoding(t *testing.T) {
+	g := Goblin(t)
+
+	teamCallback := func(cur, _, _ Object) Object { return cur.(map[string]Object)["team"] }
+	players := Seq{
+		map[string]Object{"name": "ann", "team": "red"},
+		map[string]Object{"name": "bob", "team": "blue"},
+		map[string]Object{"name": "cid", "team": "red"},
+	}
+
+	g.Describe("#EncodeGob()", func() {
+		g.It("Should round-trip Seq of registered element types", func() {
+			seq := Seq{1, "two", 3.5, gobPoint{1, 2}, Seq{map[string]Object{"p": gobPoint{3, 4}}}}
+
+			data, err := EncodeGob(seq)
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeGob(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(seq)
+		})
+
+		g.It("Should decode pointer elements as values", func() {
+			data, err := EncodeGob(Seq{&gobPoint{1, 2}})
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeGob(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(Seq{gobPoint{1, 2}})
+		})
+
+		g.It("Should round-trip the result of GroupBy", func() {
+			groups := Chain(players).GroupBy(teamCallback).Value()
+
+			data, err := EncodeGob(groups)
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeGob(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(groups)
+		})
+
+		g.It("Should work with plain gob encoder after registration", func() {
+			type document struct {
+				Items Seq
+			}
+
+			seq := Seq{gobPoint{5, 6}, int64(7)}
+			RegisterGob(seq)
+
+			var buf bytes.Buffer
+			g.Assert(gob.NewEncoder(&buf).Encode(document{Items: seq}) == nil).IsTrue()
+
+			var decoded document
+			g.Assert(gob.NewDecoder(&buf).Decode(&decoded) == nil).IsTrue()
+			g.Assert(decoded.Items).Equal(seq)
+		})
+
+		g.It("Should register values and pointers of the same type once", func() {
+			type gobLabel struct{ Name string }
+
+			RegisterGob(&gobLabel{"a"}, gobLabel{"b"}, Seq{&gobLabel{"c"}}, Seq{}, map[string]Object{})
+			RegisterGob(gobLabel{"d"})
+
+			data, err := EncodeGob(Seq{gobLabel{"e"}})
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeGob(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(Seq{gobLabel{"e"}})
+		})
+
+		g.It("Should return an error for malformed data", func() {
+			_, err := DecodeGob([]byte{1, 2, 3})
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+
+	g.Describe("#EncodeBinary()", func() {
+		g.It("Should round-trip all of the supported types", func() {
+			seq := Seq{
+				nil, true, false,
+				0, -1, math.MaxInt64, int8(-128), int16(-300), int32(1 << 30), int64(math.MinInt64),
+				uint(7), uint8(255), uint16(65535), uint32(1 << 31), uint64(math.MaxUint64),
+				float32(1.5), math.Inf(-1), "", "строка", []byte{0, 1},
+				Seq{Seq{}}, []interface{}{1, "a"},
+				map[string]Object{"a": 1}, map[string]interface{}{"b": Seq{2}},
+			}
+
+			data, err := EncodeBinary(seq)
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeBinary(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(seq)
+		})
+
+		g.It("Should round-trip the result of GroupBy", func() {
+			groups := GroupBy(Seq{1, 2, 3, 4, 5}, func(cur, _, _ Object) Object { return cur.(int)%2 == 0 })
+
+			data, err := EncodeBinary(groups)
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeBinary(data)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(map[Object]Seq{false: {1, 3, 5}, true: {2, 4}})
+		})
+
+		g.It("Should be more compact than gob for primitives", func() {
+			seq := Times(100, nil)
+
+			binaryData, _ := EncodeBinary(seq)
+			gobData, _ := EncodeGob(seq)
+
+			g.Assert(len(binaryData) < len(gobData)).IsTrue()
+		})
+
+		g.It("Should return an error for unsupported types", func() {
+			_, err := EncodeBinary(Seq{1, gobPoint{}})
+			g.Assert(err.Error()).Equal("ugo: type ugo_test.gobPoint is not supported by binary codec")
+		})
+	})
+
+	g.Describe("#DecodeBinary()", func() {
+		g.It("Should return an error for malformed data", func() {
+			data, _ := EncodeBinary(Seq{"abc", 1})
+
+			_, err := DecodeBinary(data[:len(data)-2])
+			g.Assert(err == nil).IsFalse()
+
+			_, err = DecodeBinary(append(data, 0))
+			g.Assert(err.Error()).Equal("ugo: offset 13: unexpected data after the value")
+
+			_, err = DecodeBinary([]byte("json"))
+			g.Assert(err.Error()).Equal("ugo: data is not in ugo binary format")
+
+			_, err = DecodeBinary([]byte{'u', 'g', 'o', 1, 200})
+			g.Assert(err.Error()).Equal("ugo: offset 4: unknown type tag 200")
+
+			_, err = DecodeBinary([]byte{'u', 'g', 'o', 1, 17, 0xff, 0xff, 0xff, 0xff, 0x0f})
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,122 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// EqualsNotStrictSorted checks whether both of the given slices are equal, but not strictly (see #EqualsNotStrict),
+// it sorts the copies of both slices and compares them in O(n log n) operations,
+// so Comparator must define the order, not only the equality
+func EqualsNotStrictSorted(seqLeft, seqRight Seq, cb Comparator) bool {
+	if len(seqLeft) != len(seqRight) || cb == nil {
+		return false
+	}
+
+	return EqualsStrict(SortedCopy(seqLeft, cb), SortedCopy(seqRight, cb), cb)
+}
+
+// EqualsNotStrictBy checks whether both of the given slices have the same values regardless of their positions,
+// it counts the keys returned by Callback in the map, so it takes linear time, but the keys must be comparable,
+// if Callback is not passed, the values themselves are the keys
+func EqualsNotStrictBy(seqLeft, seqRight Seq, key Callback) bool {
+	if len(seqLeft) != len(seqRight) {
+		return false
+	}
+
+	return isCountedSuperset(seqLeft, seqRight, key)
+}
+
+// IsPermutation checks whether the other slice is the permutation of given one (see #EqualsNotStrictSorted)
+func IsPermutation(seq, other Seq, cb Comparator) bool {
+	return EqualsNotStrictSorted(seq, other, cb)
+}
+
+// IsSubsequence checks whether all of the values of the sub slice are present in given slice in the same order,
+// but not necessarily next to each other
+func IsSubsequence(seq, sub Seq, cb Comparator) bool {
+	if cb == nil || len(sub) > len(seq) {
+		return false
+	}
+
+	found := 0
+
+	for index := 0; index < len(seq) && found < len(sub); index++ {
+		if cb(seq[index], sub[found]) == equal {
+			found++
+		}
+	}
+
+	return found == len(sub)
+}
+
+// IsSuperset checks whether given slice contains all of the values of the other one,
+// each value must be present at least as many times as in the other slice,
+// it merges the sorted copies of both slices, so Comparator must define the order
+func IsSuperset(seq, other Seq, cb Comparator) bool {
+	if cb == nil || len(other) > len(seq) {
+		return false
+	}
+
+	sorted, sortedOther := SortedCopy(seq, cb), SortedCopy(other, cb)
+	index := 0
+
+	for _, value := range sortedOther {
+		for index < len(sorted) && cb(sorted[index], value) < equal {
+			index++
+		}
+		if index == len(sorted) || cb(sorted[index], value) != equal {
+			return false
+		}
+		index++
+	}
+
+	return true
+}
+
+// IsSupersetBy checks whether given slice contains all of the values of the other one (see #IsSuperset),
+// but counts the keys returned by Callback like #EqualsNotStrictBy
+func IsSupersetBy(seq, other Seq, key Callback) bool {
+	if len(other) > len(seq) {
+		return false
+	}
+
+	return isCountedSuperset(seq, other, key)
+}
+
+/* private methods */
+// isCountedSuperset counts the keys of given slice and takes away the keys of the other one
+func isCountedSuperset(seq, other Seq, key Callback) bool {
+	counts := make(hashCounter, len(seq))
+
+	for index, value := range seq {
+		counts.add(createKey(value, index, seq, key), 1)
+	}
+
+	for index, value := range other {
+		current := createKey(value, index, other, key)
+		if counts.count(current) == 0 {
+			return false
+		}
+		counts.remove(current, 1)
+	}
+
+	return true
+}
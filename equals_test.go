@@ -0,0 +1,114 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"strings"
+	"testing"
+)
+
+func TestEquals(t *testing.T) {
+	g := Goblin(t)
+
+	lower := func(cur Object, _ Object, _ Object) Object { return strings.ToLower(cur.(string)) }
+
+	g.Describe("#EqualsNotStrict()", func() {
+		g.It("Should work with the Comparator, which reports only equality", func() {
+			same := func(left, right Object) int {
+				if left == right {
+					return 0
+				}
+				return 1
+			}
+
+			g.Assert(EqualsNotStrict(Seq{3, 1, 2}, Seq{1, 2, 3}, same)).IsTrue()
+			g.Assert(EqualsNotStrict(Seq{3, 1, 2}, Seq{1, 2, 2}, same)).IsFalse()
+		})
+	})
+
+	g.Describe("#EqualsNotStrictSorted()", func() {
+		g.It("Should count the repeated values", func() {
+			g.Assert(EqualsNotStrictSorted(Seq{1, 1, 2}, Seq{2, 1, 1}, Compare)).IsTrue()
+			g.Assert(EqualsNotStrictSorted(Seq{1, 1, 2}, Seq{2, 2, 1}, Compare)).IsFalse()
+			g.Assert(EqualsNotStrictSorted(Seq{1, "a", nil}, Seq{nil, 1, "a"}, Compare)).IsTrue()
+			g.Assert(EqualsNotStrictSorted(nil, nil, Compare)).IsTrue()
+			g.Assert(EqualsNotStrictSorted(Seq{1}, Seq{1}, nil)).IsFalse()
+		})
+	})
+
+	g.Describe("#EqualsNotStrictBy()", func() {
+		g.It("Should compare the counts of keys", func() {
+			g.Assert(EqualsNotStrictBy(Seq{1, 1, 2}, Seq{2, 1, 1}, nil)).IsTrue()
+			g.Assert(EqualsNotStrictBy(Seq{1, 1, 2}, Seq{2, 2, 1}, nil)).IsFalse()
+			g.Assert(EqualsNotStrictBy(Seq{"A", "b"}, Seq{"B", "a"}, lower)).IsTrue()
+			g.Assert(EqualsNotStrictBy(Seq{"A", "b"}, Seq{"B", "a", "c"}, lower)).IsFalse()
+			g.Assert(EqualsNotStrictBy(nil, Seq{}, nil)).IsTrue()
+		})
+	})
+
+	g.Describe("#IsPermutation()", func() {
+		g.It("Should return true only for the reordered values", func() {
+			g.Assert(IsPermutation(Seq{3, 1, 2}, Seq{1, 2, 3}, Compare)).IsTrue()
+			g.Assert(IsPermutation(Seq{3, 1, 2}, Seq{1, 2, 2}, Compare)).IsFalse()
+			g.Assert(IsPermutation(Seq{3, 1, 2}, Seq{1, 2, 3}, nil)).IsFalse()
+		})
+	})
+
+	g.Describe("#IsSubsequence()", func() {
+		g.It("Should check the order of values", func() {
+			g.Assert(IsSubsequence(Seq{1, 2, 3, 4, 5}, Seq{2, 4, 5}, Compare)).IsTrue()
+			g.Assert(IsSubsequence(Seq{1, 2, 3, 4, 5}, Seq{4, 2}, Compare)).IsFalse()
+			g.Assert(IsSubsequence(Seq{1, 2}, Seq{}, Compare)).IsTrue()
+			g.Assert(IsSubsequence(Seq{1}, Seq{1, 1}, Compare)).IsFalse()
+			g.Assert(IsSubsequence(Seq{1}, Seq{1}, nil)).IsFalse()
+		})
+	})
+
+	g.Describe("#IsSuperset()", func() {
+		g.It("Should check the counts of values", func() {
+			g.Assert(IsSuperset(Seq{5, 1, 2, 2, 3}, Seq{2, 5, 2}, Compare)).IsTrue()
+			g.Assert(IsSuperset(Seq{5, 1, 2, 3}, Seq{2, 5, 2}, Compare)).IsFalse()
+			g.Assert(IsSuperset(Seq{5, 1, 2, 3}, Seq{4}, Compare)).IsFalse()
+			g.Assert(IsSuperset(Seq{5}, nil, Compare)).IsTrue()
+			g.Assert(IsSuperset(Seq{5}, Seq{5}, nil)).IsFalse()
+		})
+	})
+
+	g.Describe("#IsSupersetBy()", func() {
+		g.It("Should check the counts of keys", func() {
+			g.Assert(IsSupersetBy(Seq{"A", "b", "B"}, Seq{"b", "B"}, lower)).IsTrue()
+			g.Assert(IsSupersetBy(Seq{"A", "b", "C"}, Seq{"b", "B"}, lower)).IsFalse()
+			g.Assert(IsSupersetBy(Seq{1, 2, 3}, Seq{3, 1}, nil)).IsTrue()
+		})
+	})
+
+	g.Describe("#Chain()", func() {
+		g.It("Should wrap the predicates", func() {
+			g.Assert(Chain(Seq{2, 1}).IsPermutation(Seq{1, 2}, Compare).Value()).Equal(true)
+			g.Assert(Chain(Seq{1, 2, 3}).IsSubsequence(Seq{1, 3}, Compare).Value()).Equal(true)
+			g.Assert(Chain(Seq{1, 2, 3}).IsSuperset(Seq{3, 3}, Compare).Value()).Equal(false)
+		})
+	})
+}
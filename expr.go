@@ -0,0 +1,1109 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Expr is the compiled expression of the small language, used to build callbacks from strings,
+// it supports literals (1, 2.5, 'str', "str", true, false, nil), variables, field paths (it.Name, it.Tags[0], it["key"]),
+// arithmetic (+ - * / %), comparisons (== != < <= > >=), logic (&& || !), the conditional operator (a ? b : c)
+// and calls of the built-in functions: len, lower, upper, trim, contains, startsWith, endsWith, abs, min, max, compare, str and num
+//
+// Missing map keys and fields of nil evaluate to nil, integer arithmetic stays integer
+// and gives int if both of the operands are int, values are compared by #Compare
+type Expr struct {
+	source string
+	names  []string
+	eval   exprEval
+}
+
+// ExprError is the error of compiling or evaluating the expression
+type ExprError struct {
+	Source  string // Source is the text of expression
+	Pos     int    // Pos is the byte offset in Source, where the error has happened
+	Message string // Message describes the error
+}
+
+// Error returns the message with 1-based column of the error
+func (err *ExprError) Error() string {
+	return fmt.Sprintf("ugo: expression %q at column %d: %s", err.Source, err.Pos+1, err.Message)
+}
+
+// ParseExpr compiles the expression, which may use only given variable names
+func ParseExpr(source string, names ...string) (*Expr, error) {
+	return createExpr(&exprParser{lexer: exprLexer{source: source}, names: names})
+}
+
+// MustParseExpr is like #ParseExpr, but it panics if the expression can't be compiled
+func MustParseExpr(source string, names ...string) *Expr {
+	expr, err := ParseExpr(source, names...)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String returns the source of expression
+func (expr *Expr) String() string {
+	return expr.source
+}
+
+// Eval evaluates the expression with given values of variables, absent variables are nil
+func (expr *Expr) Eval(vars map[string]Object) (Object, error) {
+	slots := make([]Object, len(expr.names))
+	for index, name := range expr.names {
+		slots[index] = vars[name]
+	}
+
+	return expr.eval(slots)
+}
+
+// CompilePredicate compiles the expression into Predicate, which may use variables it, index and src,
+// the Predicate panics with *ExprError if the expression fails or doesn't return bool
+func CompilePredicate(source string) (Predicate, error) {
+	expr, err := ParseExpr(source, "it", "index", "src")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(current, currentKey, src Object) bool {
+		result := expr.mustEval(current, currentKey, src)
+		if flag, ok := result.(bool); ok {
+			return flag
+		}
+		panic(expr.createError(0, "predicate must return bool, got %T", result))
+	}, nil
+}
+
+// MustPredicate is like #CompilePredicate, but it panics if the expression can't be compiled
+func MustPredicate(source string) Predicate {
+	cb, err := CompilePredicate(source)
+	if err != nil {
+		panic(err)
+	}
+	return cb
+}
+
+// CompileCallback compiles the expression into Callback, which may use variables it, index and src,
+// the Callback panics with *ExprError if the expression fails
+func CompileCallback(source string) (Callback, error) {
+	expr, err := ParseExpr(source, "it", "index", "src")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(current, currentKey, src Object) Object {
+		return expr.mustEval(current, currentKey, src)
+	}, nil
+}
+
+// MustCallback is like #CompileCallback, but it panics if the expression can't be compiled
+func MustCallback(source string) Callback {
+	cb, err := CompileCallback(source)
+	if err != nil {
+		panic(err)
+	}
+	return cb
+}
+
+// CompileComparator compiles the expression into Comparator, which may use variables left and right,
+// the sign of the resulting number is the result of comparison, e.g. "left.Age - right.Age"
+// or "compare(left.Name, right.Name)", the Comparator panics with *ExprError if the expression fails
+// or doesn't return a number
+func CompileComparator(source string) (Comparator, error) {
+	expr, err := ParseExpr(source, "left", "right")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(left, right Object) int {
+		result := expr.mustEval(left, right)
+		if createRank(result) != rankNumber {
+			panic(expr.createError(0, "comparator must return number, got %T", result))
+		}
+		return createNumberComparison(result, 0)
+	}, nil
+}
+
+// MustComparator is like #CompileComparator, but it panics if the expression can't be compiled
+func MustComparator(source string) Comparator {
+	cb, err := CompileComparator(source)
+	if err != nil {
+		panic(err)
+	}
+	return cb
+}
+
+// CompileCollector compiles the expression into Collector, which may use variables memo, it, index and src,
+// the Collector panics with *ExprError if the expression fails
+func CompileCollector(source string) (Collector, error) {
+	expr, err := ParseExpr(source, "memo", "it", "index", "src")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(memo, current, currentKey, src Object) Object {
+		return expr.mustEval(memo, current, currentKey, src)
+	}, nil
+}
+
+// MustCollector is like #CompileCollector, but it panics if the expression can't be compiled
+func MustCollector(source string) Collector {
+	cb, err := CompileCollector(source)
+	if err != nil {
+		panic(err)
+	}
+	return cb
+}
+
+/* private methods */
+// exprEval evaluates the part of expression with values of variables in slots
+type exprEval func(slots []Object) (Object, error)
+
+// exprFunction is the built-in function of expression language
+type exprFunction struct {
+	min, max int // min and max count of arguments, negative max means any count
+	call     func(args []Object) (Object, error)
+}
+
+var exprFunctions map[string]exprFunction
+
+func init() {
+	exprFunctions = map[string]exprFunction{
+		"len":        {1, 1, exprLen},
+		"lower":      {1, 1, createStringFunction(strings.ToLower)},
+		"upper":      {1, 1, createStringFunction(strings.ToUpper)},
+		"trim":       {1, 1, createStringFunction(strings.TrimSpace)},
+		"contains":   {2, 2, exprContains},
+		"startsWith": {2, 2, createStringPredicate(strings.HasPrefix)},
+		"endsWith":   {2, 2, createStringPredicate(strings.HasSuffix)},
+		"abs":        {1, 1, exprAbs},
+		"min":        {1, -1, createExtremum(less)},
+		"max":        {1, -1, createExtremum(larger)},
+		"compare":    {2, 2, func(args []Object) (Object, error) { return Compare(args[0], args[1]), nil }},
+		"str":        {1, 1, exprStr},
+		"num":        {1, 1, exprNum},
+	}
+}
+
+// createExpr compiles the whole source of the parser
+func createExpr(parser *exprParser) (*Expr, error) {
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+
+	eval, err := parser.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.token.kind != tokenEOF {
+		return nil, parser.unexpected()
+	}
+
+	return &Expr{source: parser.lexer.source, names: parser.names, eval: eval}, nil
+}
+
+func (expr *Expr) mustEval(slots ...Object) Object {
+	result, err := expr.eval(slots)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (expr *Expr) createError(pos int, format string, args ...interface{}) *ExprError {
+	return &ExprError{Source: expr.source, Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// exprToken kinds
+const (
+	tokenEOF = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type exprToken struct {
+	kind  int
+	text  string
+	pos   int
+	value Object
+}
+
+type exprLexer struct {
+	source string
+	pos    int
+}
+
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ".", ",", "?", ":"}
+
+func (lexer *exprLexer) next() (exprToken, error) {
+	for lexer.pos < len(lexer.source) && strings.IndexByte(" \t\r\n", lexer.source[lexer.pos]) >= 0 {
+		lexer.pos++
+	}
+
+	start := lexer.pos
+	if start >= len(lexer.source) {
+		return exprToken{kind: tokenEOF, pos: start}, nil
+	}
+
+	char, _ := utf8.DecodeRuneInString(lexer.source[start:])
+
+	switch {
+	case char >= '0' && char <= '9':
+		return lexer.number()
+	case char == '\'' || char == '"':
+		return lexer.string(byte(char))
+	case char == '_' || unicode.IsLetter(char):
+		for lexer.pos < len(lexer.source) {
+			char, size := utf8.DecodeRuneInString(lexer.source[lexer.pos:])
+			if char != '_' && !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+				break
+			}
+			lexer.pos += size
+		}
+		return exprToken{kind: tokenIdent, text: lexer.source[start:lexer.pos], pos: start}, nil
+	}
+
+	for _, operator := range exprOperators {
+		if strings.HasPrefix(lexer.source[start:], operator) {
+			lexer.pos += len(operator)
+			return exprToken{kind: tokenOperator, text: operator, pos: start}, nil
+		}
+	}
+
+	return exprToken{}, lexer.createError(start, "unexpected character %q", char)
+}
+
+func (lexer *exprLexer) number() (exprToken, error) {
+	start := lexer.pos
+	isFloat := false
+
+	lexer.digits()
+	if lexer.pos+1 < len(lexer.source) && lexer.source[lexer.pos] == '.' && isDigit(lexer.source[lexer.pos+1]) {
+		isFloat = true
+		lexer.pos++
+		lexer.digits()
+	}
+
+	if lexer.pos < len(lexer.source) && (lexer.source[lexer.pos] == 'e' || lexer.source[lexer.pos] == 'E') {
+		isFloat = true
+		lexer.pos++
+		if lexer.pos < len(lexer.source) && (lexer.source[lexer.pos] == '+' || lexer.source[lexer.pos] == '-') {
+			lexer.pos++
+		}
+		if lexer.pos >= len(lexer.source) || !isDigit(lexer.source[lexer.pos]) {
+			return exprToken{}, lexer.createError(start, "malformed number %q", lexer.source[start:lexer.pos])
+		}
+		lexer.digits()
+	}
+
+	text := lexer.source[start:lexer.pos]
+	token := exprToken{kind: tokenNumber, text: text, pos: start}
+
+	if isFloat {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return exprToken{}, lexer.createError(start, "malformed number %q", text)
+		}
+		token.value = value
+		return token, nil
+	}
+
+	value, err := strconv.ParseInt(text, 10, strconv.IntSize)
+	if err != nil {
+		return exprToken{}, lexer.createError(start, "integer %s is out of range", text)
+	}
+	token.value = int(value)
+
+	return token, nil
+}
+
+func (lexer *exprLexer) digits() {
+	for lexer.pos < len(lexer.source) && isDigit(lexer.source[lexer.pos]) {
+		lexer.pos++
+	}
+}
+
+func (lexer *exprLexer) string(quote byte) (exprToken, error) {
+	start := lexer.pos
+	lexer.pos++
+
+	var value strings.Builder
+
+	for lexer.pos < len(lexer.source) {
+		char := lexer.source[lexer.pos]
+		lexer.pos++
+
+		switch char {
+		case quote:
+			return exprToken{kind: tokenString, text: lexer.source[start:lexer.pos], pos: start, value: value.String()}, nil
+		case '\\':
+			if lexer.pos >= len(lexer.source) {
+				break
+			}
+
+			escaped := lexer.source[lexer.pos]
+			lexer.pos++
+
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case '\\', '\'', '"':
+				value.WriteByte(escaped)
+			default:
+				return exprToken{}, lexer.createError(lexer.pos-2, "unknown escape sequence \\%c", escaped)
+			}
+		default:
+			value.WriteByte(char)
+		}
+	}
+
+	return exprToken{}, lexer.createError(start, "unterminated string")
+}
+
+func (lexer *exprLexer) createError(pos int, format string, args ...interface{}) *ExprError {
+	return &ExprError{Source: lexer.source, Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// exprParser compiles tokens into exprEval by recursive descent, from the lowest precedence to the highest one:
+// conditional, ||, &&, equality, comparison, additive, multiplicative, unary, postfix and primary
+type exprParser struct {
+	lexer    exprLexer
+	token    exprToken
+	names    []string
+	implicit bool // implicit makes unknown variables the fields of the first one
+}
+
+func (parser *exprParser) advance() error {
+	token, err := parser.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	parser.token = token
+	return nil
+}
+
+func (parser *exprParser) is(operator string) bool {
+	return parser.token.kind == tokenOperator && parser.token.text == operator
+}
+
+func (parser *exprParser) expect(operator string) error {
+	if !parser.is(operator) {
+		return parser.createError(parser.token.pos, "expected %q, got %s", operator, parser.describe())
+	}
+	return parser.advance()
+}
+
+func (parser *exprParser) describe() string {
+	if parser.token.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(parser.token.text)
+}
+
+func (parser *exprParser) unexpected() error {
+	return parser.createError(parser.token.pos, "unexpected %s", parser.describe())
+}
+
+func (parser *exprParser) createError(pos int, format string, args ...interface{}) *ExprError {
+	return parser.lexer.createError(pos, format, args...)
+}
+
+func (parser *exprParser) expression() (exprEval, error) {
+	condition, err := parser.binary(0)
+	if err != nil || !parser.is("?") {
+		return condition, err
+	}
+
+	pos := parser.token.pos
+	if err = parser.advance(); err != nil {
+		return nil, err
+	}
+
+	then, err := parser.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = parser.expect(":"); err != nil {
+		return nil, err
+	}
+
+	otherwise, err := parser.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(slots []Object) (Object, error) {
+		value, err := condition(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, parser.createError(pos, "condition must be bool, got %T", value)
+		}
+
+		if flag {
+			return then(slots)
+		}
+		return otherwise(slots)
+	}, nil
+}
+
+// exprLevels are binary operators by precedence, from the lowest one
+var exprLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (parser *exprParser) binary(level int) (exprEval, error) {
+	if level == len(exprLevels) {
+		return parser.unary()
+	}
+
+	left, err := parser.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := ""
+		for _, candidate := range exprLevels[level] {
+			if parser.is(candidate) {
+				operator = candidate
+			}
+		}
+
+		if operator == "" {
+			return left, nil
+		}
+
+		pos := parser.token.pos
+		if err = parser.advance(); err != nil {
+			return nil, err
+		}
+
+		right, err := parser.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = parser.createBinary(operator, pos, left, right)
+	}
+}
+
+func (parser *exprParser) createBinary(operator string, pos int, left, right exprEval) exprEval {
+	if operator == "&&" || operator == "||" {
+		return func(slots []Object) (Object, error) {
+			value, err := parser.evalBool(left, slots, pos, operator)
+			if err != nil || value == (operator == "||") {
+				return value, err
+			}
+			return parser.evalBool(right, slots, pos, operator)
+		}
+	}
+
+	return func(slots []Object) (Object, error) {
+		leftValue, err := left(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		rightValue, err := right(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case "==":
+			return exprEquals(leftValue, rightValue), nil
+		case "!=":
+			return !exprEquals(leftValue, rightValue), nil
+		}
+
+		var result Object
+		if operator == "<" || operator == "<=" || operator == ">" || operator == ">=" {
+			result, err = exprCompare(operator, leftValue, rightValue)
+		} else {
+			result, err = exprArithmetic(operator, leftValue, rightValue)
+		}
+
+		if err != nil {
+			return nil, parser.createError(pos, "%v", err)
+		}
+
+		return result, nil
+	}
+}
+
+func (parser *exprParser) evalBool(eval exprEval, slots []Object, pos int, operator string) (bool, error) {
+	value, err := eval(slots)
+	if err != nil {
+		return false, err
+	}
+
+	flag, ok := value.(bool)
+	if !ok {
+		return false, parser.createError(pos, "operator %s needs bool operands, got %T", operator, value)
+	}
+
+	return flag, nil
+}
+
+func exprCompare(operator string, left, right Object) (Object, error) {
+	leftRank, rightRank := createRank(left), createRank(right)
+
+	if leftRank != rightRank || (leftRank != rankNumber && leftRank != rankString) {
+		return nil, fmt.Errorf("operator %s is not defined for %T and %T", operator, left, right)
+	}
+
+	result := Compare(left, right)
+
+	switch operator {
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	}
+	return result >= 0, nil
+}
+
+func exprArithmetic(operator string, left, right Object) (Object, error) {
+	if operator == "+" {
+		leftString, leftOk := left.(string)
+		rightString, rightOk := right.(string)
+
+		if leftOk && rightOk {
+			return leftString + rightString, nil
+		}
+	}
+
+	if createRank(left) != rankNumber || createRank(right) != rankNumber {
+		return nil, fmt.Errorf("operator %s is not defined for %T and %T", operator, left, right)
+	}
+
+	leftInt, leftIsInt := toInt64(left)
+	rightInt, rightIsInt := toInt64(right)
+
+	if leftIsInt && rightIsInt && !isFloatNumber(left) && !isFloatNumber(right) {
+		var result int64
+
+		switch operator {
+		case "+":
+			result = leftInt + rightInt
+		case "-":
+			result = leftInt - rightInt
+		case "*":
+			result = leftInt * rightInt
+		case "/", "%":
+			if rightInt == 0 {
+				return nil, fmt.Errorf("integer division by zero")
+			}
+			if operator == "/" {
+				result = leftInt / rightInt
+			} else {
+				result = leftInt % rightInt
+			}
+		}
+
+		_, leftIsPlain := left.(int)
+		_, rightIsPlain := right.(int)
+		if leftIsPlain && rightIsPlain {
+			return int(result), nil
+		}
+		return result, nil
+	}
+
+	leftFloat, _ := toComparableFloat64(left)
+	rightFloat, _ := toComparableFloat64(right)
+
+	switch operator {
+	case "+":
+		return leftFloat + rightFloat, nil
+	case "-":
+		return leftFloat - rightFloat, nil
+	case "*":
+		return leftFloat * rightFloat, nil
+	case "/":
+		return leftFloat / rightFloat, nil
+	}
+	return math.Mod(leftFloat, rightFloat), nil
+}
+
+func (parser *exprParser) unary() (exprEval, error) {
+	if !parser.is("!") && !parser.is("-") {
+		return parser.postfix()
+	}
+
+	operator, pos := parser.token.text, parser.token.pos
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+
+	operand, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == "!" {
+		return func(slots []Object) (Object, error) {
+			value, err := parser.evalBool(operand, slots, pos, operator)
+			return !value, err
+		}, nil
+	}
+
+	return func(slots []Object) (Object, error) {
+		value, err := operand(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, err = exprArithmetic("-", 0, value); err != nil {
+			return nil, parser.createError(pos, "%v", err)
+		}
+		return value, nil
+	}, nil
+}
+
+func (parser *exprParser) postfix() (exprEval, error) {
+	target, err := parser.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		pos := parser.token.pos
+
+		switch {
+		case parser.is("."):
+			if err = parser.advance(); err != nil {
+				return nil, err
+			}
+
+			if parser.token.kind != tokenIdent {
+				return nil, parser.createError(parser.token.pos, "expected field name, got %s", parser.describe())
+			}
+
+			name := parser.token.text
+			if err = parser.advance(); err != nil {
+				return nil, err
+			}
+
+			target = parser.createAccess(target, pos, func([]Object) (Object, error) { return name, nil })
+		case parser.is("["):
+			if err = parser.advance(); err != nil {
+				return nil, err
+			}
+
+			key, err := parser.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = parser.expect("]"); err != nil {
+				return nil, err
+			}
+
+			target = parser.createAccess(target, pos, key)
+		default:
+			return target, nil
+		}
+	}
+}
+
+func (parser *exprParser) createAccess(target exprEval, pos int, key exprEval) exprEval {
+	return func(slots []Object) (Object, error) {
+		value, err := target(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := key(slots)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := exprAccess(value, index)
+		if err != nil {
+			return nil, parser.createError(pos, "%v", err)
+		}
+
+		return result, nil
+	}
+}
+
+func (parser *exprParser) primary() (exprEval, error) {
+	token := parser.token
+
+	switch token.kind {
+	case tokenNumber, tokenString:
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		return createConstant(token.value), nil
+	case tokenIdent:
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+
+		switch token.text {
+		case "true", "false":
+			return createConstant(token.text == "true"), nil
+		case "nil", "null":
+			return createConstant(nil), nil
+		}
+
+		if parser.is("(") {
+			return parser.call(token)
+		}
+
+		for slot, name := range parser.names {
+			if name == token.text {
+				return func(slots []Object) (Object, error) { return slots[slot], nil }, nil
+			}
+		}
+
+		if parser.implicit && len(parser.names) > 0 {
+			name := createConstant(token.text)
+			return parser.createAccess(func(slots []Object) (Object, error) { return slots[0], nil }, token.pos, name), nil
+		}
+
+		if len(parser.names) == 0 {
+			return nil, parser.createError(token.pos, "unknown variable %s", token.text)
+		}
+		return nil, parser.createError(token.pos, "unknown variable %s, expected one of %s", token.text, strings.Join(parser.names, ", "))
+	case tokenOperator:
+		if token.text == "(" {
+			if err := parser.advance(); err != nil {
+				return nil, err
+			}
+
+			inner, err := parser.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			return inner, parser.expect(")")
+		}
+	}
+
+	return nil, parser.unexpected()
+}
+
+func (parser *exprParser) call(name exprToken) (exprEval, error) {
+	function, ok := exprFunctions[name.text]
+	if !ok {
+		return nil, parser.createError(name.pos, "unknown function %s", name.text)
+	}
+
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+
+	var args []exprEval
+
+	for !parser.is(")") {
+		if len(args) > 0 {
+			if err := parser.expect(","); err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := parser.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+
+	if len(args) < function.min || (function.max >= 0 && len(args) > function.max) {
+		return nil, parser.createError(name.pos, "function %s doesn't take %d arguments", name.text, len(args))
+	}
+
+	return func(slots []Object) (Object, error) {
+		values := make([]Object, len(args))
+
+		for index, arg := range args {
+			value, err := arg(slots)
+			if err != nil {
+				return nil, err
+			}
+			values[index] = value
+		}
+
+		result, err := function.call(values)
+		if err != nil {
+			return nil, parser.createError(name.pos, "%s: %v", name.text, err)
+		}
+
+		return result, nil
+	}, nil
+}
+
+func createConstant(value Object) exprEval {
+	return func([]Object) (Object, error) { return value, nil }
+}
+
+func isFloatNumber(value Object) bool {
+	kind := reflect.ValueOf(value).Kind()
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// exprEquals compares primitives by #Compare and the rest of values deeply
+func exprEquals(left, right Object) bool {
+	leftRank, rightRank := createRank(left), createRank(right)
+
+	if leftRank != rankOther && rightRank != rankOther {
+		return leftRank == rightRank && Compare(left, right) == equal
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// exprAccess returns the field or element of value by key, nil values and missing map keys give nil
+func exprAccess(value, key Object) (Object, error) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]Object:
+		if name, ok := key.(string); ok {
+			return typed[name], nil
+		}
+	case map[string]interface{}:
+		if name, ok := key.(string); ok {
+			return typed[name], nil
+		}
+	}
+
+	reflected := reflect.ValueOf(value)
+	for reflected.Kind() == reflect.Ptr || reflected.Kind() == reflect.Interface {
+		if reflected.IsNil() {
+			return nil, nil
+		}
+		reflected = reflected.Elem()
+	}
+
+	switch reflected.Kind() {
+	case reflect.Struct:
+		name, ok := key.(string)
+		if !ok {
+			break
+		}
+
+		field, found := reflected.Type().FieldByName(name)
+		if !found || field.PkgPath != "" {
+			return nil, fmt.Errorf("%s has no exported field %s", reflected.Type(), name)
+		}
+
+		return reflected.FieldByIndex(field.Index).Interface(), nil
+	case reflect.Map:
+		keyType := reflected.Type().Key()
+		keyValue := reflect.ValueOf(key)
+
+		switch {
+		case key == nil:
+			return nil, nil
+		case keyValue.Type().AssignableTo(keyType):
+		case keyType.Kind() == reflect.String && keyValue.Kind() == reflect.String,
+			createRank(key) == rankNumber && createRank(reflect.Zero(keyType).Interface()) == rankNumber && keyValue.Type().ConvertibleTo(keyType):
+			keyValue = keyValue.Convert(keyType)
+		default:
+			return nil, nil
+		}
+
+		if found := reflected.MapIndex(keyValue); found.IsValid() {
+			return found.Interface(), nil
+		}
+		return nil, nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		index, ok := toInt64(key)
+		if !ok || isFloatNumber(key) {
+			break
+		}
+
+		if reflected.Kind() == reflect.String {
+			runes := []rune(reflected.String())
+			if index < 0 || index >= int64(len(runes)) {
+				return nil, nil
+			}
+			return string(runes[index]), nil
+		}
+
+		if index < 0 || index >= int64(reflected.Len()) {
+			return nil, nil
+		}
+		return reflected.Index(int(index)).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("can't access %v of %T", key, value)
+}
+
+func exprLen(args []Object) (Object, error) {
+	if args[0] == nil {
+		return 0, nil
+	}
+
+	if text, ok := args[0].(string); ok {
+		return utf8.RuneCountInString(text), nil
+	}
+
+	reflected := reflect.ValueOf(args[0])
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+		return reflected.Len(), nil
+	}
+
+	return nil, fmt.Errorf("%T has no length", args[0])
+}
+
+func exprContains(args []Object) (Object, error) {
+	if text, ok := args[0].(string); ok {
+		part, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", args[1])
+		}
+		return strings.Contains(text, part), nil
+	}
+
+	reflected := reflect.ValueOf(args[0])
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < reflected.Len(); index++ {
+			if exprEquals(reflected.Index(index).Interface(), args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		found, err := exprAccess(args[0], args[1])
+		return err == nil && found != nil, nil
+	}
+
+	return nil, fmt.Errorf("%T can't contain anything", args[0])
+}
+
+func exprAbs(args []Object) (Object, error) {
+	if createRank(args[0]) != rankNumber {
+		return nil, fmt.Errorf("expected number, got %T", args[0])
+	}
+
+	if Compare(args[0], 0) >= 0 {
+		return args[0], nil
+	}
+
+	return exprArithmetic("-", 0, args[0])
+}
+
+func exprStr(args []Object) (Object, error) {
+	if args[0] == nil {
+		return "", nil
+	}
+	return fmt.Sprint(args[0]), nil
+}
+
+func exprNum(args []Object) (Object, error) {
+	if createRank(args[0]) == rankNumber {
+		return args[0], nil
+	}
+
+	text, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("can't convert %T to number", args[0])
+	}
+
+	text = strings.TrimSpace(text)
+	if integral, err := strconv.Atoi(text); err == nil {
+		return integral, nil
+	}
+
+	float, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %q to number", text)
+	}
+
+	return float, nil
+}
+
+func createStringFunction(cb func(string) string) func(args []Object) (Object, error) {
+	return func(args []Object) (Object, error) {
+		text, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", args[0])
+		}
+		return cb(text), nil
+	}
+}
+
+func createStringPredicate(cb func(string, string) bool) func(args []Object) (Object, error) {
+	return func(args []Object) (Object, error) {
+		text, textOk := args[0].(string)
+		part, partOk := args[1].(string)
+		if !textOk || !partOk {
+			return nil, fmt.Errorf("expected strings, got %T and %T", args[0], args[1])
+		}
+		return cb(text, part), nil
+	}
+}
+
+func createExtremum(dir int) func(args []Object) (Object, error) {
+	return func(args []Object) (Object, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if Compare(arg, result) == dir {
+				result = arg
+			}
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,189 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+type exprPerson struct {
+	Name   string
+	Age    int
+	Tags   []string
+	Parent *exprPerson
+	secret string
+}
+
+func TestExpressions(t *testing.T) {
+	g := Goblin(t)
+
+	people := Seq{
+		exprPerson{Name: "ann", Age: 31, Tags: []string{"admin"}},
+		exprPerson{Name: "", Age: 40},
+		exprPerson{Name: "bob", Age: 17, Parent: &exprPerson{Name: "cid", Age: 45}},
+	}
+
+	eval := func(source string, vars map[string]Object) Object {
+		names := []string{}
+		for name := range vars {
+			names = append(names, name)
+		}
+		value, _ := MustParseExpr(source, names...).Eval(vars)
+		return value
+	}
+
+	evalError := func(source string, names ...string) string {
+		expr, err := ParseExpr(source, names...)
+		if err != nil {
+			return err.Error()
+		}
+
+		_, err = expr.Eval(map[string]Object{"it": people[2]})
+		return err.Error()
+	}
+
+	g.Describe("#MustPredicate()", func() {
+		g.It("Should filter by fields of structs", func() {
+			adults := Filter(people, MustPredicate("it.Age > 18 && it.Name != ''"))
+
+			g.Assert(adults).Equal(Seq{people[0]})
+		})
+
+		g.It("Should filter by arithmetic on elements", func() {
+			g.Assert(Filter(Seq{1, 2, 3, 4, 5}, MustPredicate("it % 2 != 0"))).Equal(Seq{1, 3, 5})
+			g.Assert(Filter(Seq{1, 2, 3, 4, 5}, MustPredicate("index >= 3 || !(it < 2)"))).Equal(Seq{2, 3, 4, 5})
+		})
+
+		g.It("Should panic if the expression can't be compiled", func() {
+			defer func() {
+				g.Assert(recover().(*ExprError).Pos).Equal(9)
+			}()
+
+			MustPredicate("it.Age > ")
+		})
+
+		g.It("Should panic with ExprError if the result is not bool", func() {
+			defer func() {
+				g.Assert(recover().(error).Error()).Equal(`ugo: expression "it + 1" at column 1: predicate must return bool, got int`)
+			}()
+
+			Filter(Seq{1}, MustPredicate("it + 1"))
+		})
+	})
+
+	g.Describe("#MustCallback()", func() {
+		g.It("Should map elements by paths and functions", func() {
+			names := Map(people, MustCallback("it.Parent.Name"))
+			g.Assert(names).Equal(Seq{nil, nil, "cid"})
+
+			labels := Map(people, MustCallback(`upper(it.Name) + ":" + str(it.Age) + (len(it.Tags) > 0 ? "*" : "")`))
+			g.Assert(labels).Equal(Seq{"ANN:31*", ":40", "BOB:17"})
+		})
+
+		g.It("Should access elements of maps and slices", func() {
+			seq := Seq{map[string]Object{"stats": Seq{1, 2}, "name": "ann"}, map[string]interface{}{"name": "bob"}}
+
+			g.Assert(Map(seq, MustCallback(`it.stats[1]`))).Equal(Seq{2, nil})
+			g.Assert(Map(seq, MustCallback(`it["name"][0]`))).Equal(Seq{"a", "b"})
+		})
+	})
+
+	g.Describe("#MustComparator()", func() {
+		g.It("Should sort by the sign of expression", func() {
+			byAge := SortBy(Seq{people[0], people[1], people[2]}, MustComparator("left.Age - right.Age"))
+			g.Assert(Map(byAge, MustCallback("it.Age"))).Equal(Seq{17, 31, 40})
+
+			byName := SortBy(Seq{"b", "c", "a"}, MustComparator("-compare(left, right)"))
+			g.Assert(byName).Equal(Seq{"c", "b", "a"})
+		})
+	})
+
+	g.Describe("#MustCollector()", func() {
+		g.It("Should reduce by expression", func() {
+			g.Assert(Reduce(Seq{1, 2, 3}, MustCollector("memo + it * index"), 0)).Equal(8)
+			g.Assert(Reduce(Seq{"a", "b"}, MustCollector("memo + it"), "")).Equal("ab")
+		})
+	})
+
+	g.Describe("#Expr", func() {
+		g.It("Should evaluate literals and operators", func() {
+			g.Assert(eval("1 + 2 * 3 - 4 / 2", nil)).Equal(5)
+			g.Assert(eval("(1 + 2) * 3 % 4", nil)).Equal(1)
+			g.Assert(eval("7 / 2.0", nil)).Equal(3.5)
+			g.Assert(eval("1.5e1 - -1", nil)).Equal(16.0)
+			g.Assert(eval("x + 1", map[string]Object{"x": int64(1)})).Equal(int64(2))
+			g.Assert(eval("x * 2", map[string]Object{"x": uint8(3)})).Equal(int64(6))
+			g.Assert(eval(`'it\'s' + "\t\"quoted\""`, nil)).Equal("it's\t\"quoted\"")
+			g.Assert(eval("1 == 1.0 && 'a' < 'b' && nil == null && true != false", nil)).Equal(true)
+			g.Assert(eval("false && 1 / 0 == 0", nil)).Equal(false)
+			g.Assert(eval("true || x.y.z", map[string]Object{"x": nil})).Equal(true)
+			g.Assert(eval("x == y", map[string]Object{"x": Seq{1}, "y": Seq{1}})).Equal(true)
+			g.Assert(eval("x.missing == nil", map[string]Object{"x": map[string]Object{}})).Equal(true)
+		})
+
+		g.It("Should call built-in functions", func() {
+			g.Assert(eval("len('тест') + len(x)", map[string]Object{"x": Seq{1, 2}})).Equal(6)
+			g.Assert(eval("trim(lower(' AbC '))", nil)).Equal("abc")
+			g.Assert(eval("contains('team', 'ea') && contains(x, 2) && !contains(x, 3)", map[string]Object{"x": Seq{1, 2}})).Equal(true)
+			g.Assert(eval("startsWith('ugo', 'u') && endsWith('ugo', 'go')", nil)).Equal(true)
+			g.Assert(eval("abs(-3) + abs(2.5)", nil)).Equal(5.5)
+			g.Assert(eval("min(3, 1, 2) + max(3, 10, 2)", nil)).Equal(11)
+			g.Assert(eval("num(' 12 ') + num('0.5')", nil)).Equal(12.5)
+			g.Assert(eval("contains(x, 'a')", map[string]Object{"x": map[string]int{"a": 1}})).Equal(true)
+		})
+
+		g.It("Should keep the source", func() {
+			g.Assert(MustParseExpr("it.Age", "it").String()).Equal("it.Age")
+		})
+	})
+
+	g.Describe("#ExprError", func() {
+		g.It("Should report compile errors with positions", func() {
+			g.Assert(evalError("it.Age >", "it")).Equal(`ugo: expression "it.Age >" at column 9: unexpected end of expression`)
+			g.Assert(evalError("it.Age >> 1", "it")).Equal(`ugo: expression "it.Age >> 1" at column 9: unexpected ">"`)
+			g.Assert(evalError("age > 1", "it")).Equal(`ugo: expression "age > 1" at column 1: unknown variable age, expected one of it`)
+			g.Assert(evalError("it")).Equal(`ugo: expression "it" at column 1: unknown variable it`)
+			g.Assert(evalError("it.", "it")).Equal(`ugo: expression "it." at column 4: expected field name, got end of expression`)
+			g.Assert(evalError("'abc", "it")).Equal(`ugo: expression "'abc" at column 1: unterminated string`)
+			g.Assert(evalError("it # 1", "it")).Equal(`ugo: expression "it # 1" at column 4: unexpected character '#'`)
+			g.Assert(evalError("len(it, 1)", "it")).Equal(`ugo: expression "len(it, 1)" at column 1: function len doesn't take 2 arguments`)
+			g.Assert(evalError("size(it)", "it")).Equal(`ugo: expression "size(it)" at column 1: unknown function size`)
+			g.Assert(evalError("(it", "it")).Equal(`ugo: expression "(it" at column 4: expected ")", got end of expression`)
+			g.Assert(evalError("it ? 1", "it")).Equal(`ugo: expression "it ? 1" at column 7: expected ":", got end of expression`)
+			g.Assert(evalError("99999999999999999999", "it")).Equal(`ugo: expression "99999999999999999999" at column 1: integer 99999999999999999999 is out of range`)
+		})
+
+		g.It("Should report runtime errors with positions", func() {
+			g.Assert(evalError("it.Age / 0", "it")).Equal(`ugo: expression "it.Age / 0" at column 8: integer division by zero`)
+			g.Assert(evalError("it.Name - 1", "it")).Equal(`ugo: expression "it.Name - 1" at column 9: operator - is not defined for string and int`)
+			g.Assert(evalError("it.Height", "it")).Equal(`ugo: expression "it.Height" at column 3: ugo_test.exprPerson has no exported field Height`)
+			g.Assert(evalError("it.secret", "it")).Equal(`ugo: expression "it.secret" at column 3: ugo_test.exprPerson has no exported field secret`)
+			g.Assert(evalError("it.Age && true", "it")).Equal(`ugo: expression "it.Age && true" at column 8: operator && needs bool operands, got int`)
+			g.Assert(evalError("it.Age < 'a'", "it")).Equal(`ugo: expression "it.Age < 'a'" at column 8: operator < is not defined for int and string`)
+			g.Assert(evalError("upper(it.Age)", "it")).Equal(`ugo: expression "upper(it.Age)" at column 1: upper: expected string, got int`)
+			g.Assert(evalError("it.Age ? 1 : 2", "it")).Equal(`ugo: expression "it.Age ? 1 : 2" at column 8: condition must be bool, got int`)
+		})
+	})
+}
@@ -0,0 +1,493 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Function is an alias type for function, which takes one value and returns another one
+type Function func(arg Object) Object
+
+// Variadic is an alias type for function, which takes any count of values and returns one value
+type Variadic func(args ...Object) Object
+
+// Placeholder is the special value, which leaves an argument open in #Partial
+var Placeholder Object = placeholder{}
+
+// Negate returns the given Predicate but with opposite result
+func Negate(cb Predicate) Predicate {
+	if cb == nil {
+		return nil
+	}
+	return func(cur, index, list Object) bool { return !cb(cur, index, list) }
+}
+
+// NegateComparator returns the given Comparator but with opposite order
+func NegateComparator(cb Comparator) Comparator {
+	if cb == nil {
+		return nil
+	}
+	return func(left, right Object) int { return cb(right, left) }
+}
+
+// Memoize returns Function, which caches results of the given one by keys, calculated by key Function,
+// if key Function is not passed, the argument is the key, so it should be comparable,
+// results for the keys, which are not comparable (Seq, maps, etc.), are not cached, but calculated each time,
+// if size is positive, it keeps only size least recently used results
+func Memoize(fn Function, key Function, size int) Function {
+	if fn == nil {
+		return nil
+	}
+
+	cache := newMemoCache(size)
+
+	return func(arg Object) Object {
+		var cacheKey Object = arg
+		if key != nil {
+			cacheKey = key(arg)
+		}
+
+		if result, ok := cache.get(cacheKey); ok {
+			return result
+		}
+
+		result := fn(arg)
+		cache.put(cacheKey, result)
+
+		return result
+	}
+}
+
+// MemoizeCallback returns Callback, which caches results of the given one (see #Memoize),
+// if key Callback is not passed, the current value is the key, so Seq or map values are not cached,
+// pass key Callback, which returns comparable keys, to cache them
+func MemoizeCallback(cb Callback, key Callback, size int) Callback {
+	if cb == nil {
+		return nil
+	}
+
+	cache := newMemoCache(size)
+
+	return func(cur, index, list Object) Object {
+		var cacheKey Object = cur
+		if key != nil {
+			cacheKey = key(cur, index, list)
+		}
+
+		if result, ok := cache.get(cacheKey); ok {
+			return result
+		}
+
+		result := cb(cur, index, list)
+		cache.put(cacheKey, result)
+
+		return result
+	}
+}
+
+// MemoizePredicate returns Predicate, which caches results of the given one (see #Memoize),
+// if key Callback is not passed, the current value is the key
+func MemoizePredicate(cb Predicate, key Callback, size int) Predicate {
+	if cb == nil {
+		return nil
+	}
+
+	memoized := MemoizeCallback(func(cur, index, list Object) Object { return cb(cur, index, list) }, key, size)
+
+	return func(cur, index, list Object) bool { return memoized(cur, index, list).(bool) }
+}
+
+// MemoizeComparator returns Comparator, which caches results of the given one (see #Memoize),
+// the key is the pair of keys of both values, if key Function is not passed, values are the keys
+func MemoizeComparator(cb Comparator, key Function, size int) Comparator {
+	if cb == nil {
+		return nil
+	}
+
+	cache := newMemoCache(size)
+
+	return func(left, right Object) int {
+		cacheKey := [2]Object{left, right}
+		if key != nil {
+			cacheKey = [2]Object{key(left), key(right)}
+		}
+
+		if result, ok := cache.get(cacheKey); ok {
+			return result.(int)
+		}
+
+		result := cb(left, right)
+		cache.put(cacheKey, result)
+
+		return result
+	}
+}
+
+// Once returns Function, which calls the given one only the first time
+// and returns its first result for all the next calls
+func Once(fn Function) Function {
+	if fn == nil {
+		return nil
+	}
+
+	var once sync.Once
+	var result Object
+
+	return func(arg Object) Object {
+		once.Do(func() { result = fn(arg) })
+		return result
+	}
+}
+
+// OnceAction returns Action, which calls the given one only the first time
+func OnceAction(cb Action) Action {
+	if cb == nil {
+		return nil
+	}
+
+	var once sync.Once
+
+	return func(cur, index, list Object) {
+		once.Do(func() { cb(cur, index, list) })
+	}
+}
+
+// OnceCallback returns Callback, which calls the given one only the first time (see #Once)
+func OnceCallback(cb Callback) Callback {
+	if cb == nil {
+		return nil
+	}
+
+	var once sync.Once
+	var result Object
+
+	return func(cur, index, list Object) Object {
+		once.Do(func() { result = cb(cur, index, list) })
+		return result
+	}
+}
+
+// OncePredicate returns Predicate, which calls the given one only the first time (see #Once)
+func OncePredicate(cb Predicate) Predicate {
+	if cb == nil {
+		return nil
+	}
+
+	once := OnceCallback(func(cur, index, list Object) Object { return cb(cur, index, list) })
+
+	return func(cur, index, list Object) bool { return once(cur, index, list).(bool) }
+}
+
+// After returns Action, which calls the given one only starting from the n-th call
+func After(n int, cb Action) Action {
+	if cb == nil {
+		return nil
+	}
+
+	var calls int64
+
+	return func(cur, index, list Object) {
+		if atomic.AddInt64(&calls, 1) >= int64(n) {
+			cb(cur, index, list)
+		}
+	}
+}
+
+// Before returns Action, which calls the given one only while it's called less than n times
+func Before(n int, cb Action) Action {
+	if cb == nil {
+		return nil
+	}
+
+	var calls int64
+
+	return func(cur, index, list Object) {
+		if atomic.AddInt64(&calls, 1) < int64(n) {
+			cb(cur, index, list)
+		}
+	}
+}
+
+// AfterFunction returns Function, which calls the given one only starting from the n-th call,
+// it returns nil for the calls before it
+func AfterFunction(n int, fn Function) Function {
+	if fn == nil {
+		return nil
+	}
+
+	after := AfterCallback(n, func(cur, _, _ Object) Object { return fn(cur) })
+
+	return func(arg Object) Object { return after(arg, nil, nil) }
+}
+
+// AfterCallback returns Callback, which calls the given one only starting from the n-th call,
+// it returns nil for the calls before it
+func AfterCallback(n int, cb Callback) Callback {
+	if cb == nil {
+		return nil
+	}
+
+	var calls int64
+
+	return func(cur, index, list Object) Object {
+		if atomic.AddInt64(&calls, 1) >= int64(n) {
+			return cb(cur, index, list)
+		}
+		return nil
+	}
+}
+
+// AfterPredicate returns Predicate, which calls the given one only starting from the n-th call,
+// it returns false for the calls before it
+func AfterPredicate(n int, cb Predicate) Predicate {
+	if cb == nil {
+		return nil
+	}
+
+	after := AfterCallback(n, func(cur, index, list Object) Object { return cb(cur, index, list) })
+
+	return func(cur, index, list Object) bool {
+		result, _ := after(cur, index, list).(bool)
+		return result
+	}
+}
+
+// BeforeFunction returns Function, which calls the given one only while it's called less than n times,
+// the next calls return the result of the last one
+func BeforeFunction(n int, fn Function) Function {
+	if fn == nil {
+		return nil
+	}
+
+	before := BeforeCallback(n, func(cur, _, _ Object) Object { return fn(cur) })
+
+	return func(arg Object) Object { return before(arg, nil, nil) }
+}
+
+// BeforeCallback returns Callback, which calls the given one only while it's called less than n times,
+// the next calls return the result of the last one
+func BeforeCallback(n int, cb Callback) Callback {
+	if cb == nil {
+		return nil
+	}
+
+	var calls int64
+	var mutex sync.Mutex
+	var last Object
+
+	return func(cur, index, list Object) Object {
+		if atomic.AddInt64(&calls, 1) < int64(n) {
+			result := cb(cur, index, list)
+
+			mutex.Lock()
+			last = result
+			mutex.Unlock()
+
+			return result
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		return last
+	}
+}
+
+// BeforePredicate returns Predicate, which calls the given one only while it's called less than n times,
+// the next calls return the result of the last one, or false if there was none
+func BeforePredicate(n int, cb Predicate) Predicate {
+	if cb == nil {
+		return nil
+	}
+
+	before := BeforeCallback(n, func(cur, index, list Object) Object { return cb(cur, index, list) })
+
+	return func(cur, index, list Object) bool {
+		result, _ := before(cur, index, list).(bool)
+		return result
+	}
+}
+
+// Compose returns Function, which calls given Functions from the last one to the first one,
+// passing the result of each of them to the previous one, e. g. Compose(f, g)(x) is f(g(x))
+func Compose(fns ...Function) Function {
+	return func(arg Object) Object {
+		for i := len(fns) - 1; i >= 0; i-- {
+			if fns[i] != nil {
+				arg = fns[i](arg)
+			}
+		}
+		return arg
+	}
+}
+
+// ComposeCallback returns Callback, which calls given Callbacks from the last one to the first one,
+// passing the result of each of them as the current value of the previous one
+func ComposeCallback(cbs ...Callback) Callback {
+	return func(cur, index, list Object) Object {
+		for i := len(cbs) - 1; i >= 0; i-- {
+			if cbs[i] != nil {
+				cur = cbs[i](cur, index, list)
+			}
+		}
+		return cur
+	}
+}
+
+// ComposePredicate returns Predicate, which checks the result of given Callbacks, composed by #ComposeCallback
+func ComposePredicate(cb Predicate, cbs ...Callback) Predicate {
+	if cb == nil {
+		return nil
+	}
+
+	composed := ComposeCallback(cbs...)
+
+	return func(cur, index, list Object) bool { return cb(composed(cur, index, list), index, list) }
+}
+
+// ComposeComparator returns Comparator, which compares the results of given Functions, composed by #Compose,
+// e. g. ComposeComparator(cb, f, g)(l, r) is cb(f(g(l)), f(g(r)))
+func ComposeComparator(cb Comparator, fns ...Function) Comparator {
+	if cb == nil {
+		return nil
+	}
+
+	composed := Compose(fns...)
+
+	return func(left, right Object) int { return cb(composed(left), composed(right)) }
+}
+
+// Partial returns Variadic, which calls the given one with bound arguments first,
+// every Placeholder among bound arguments is filled with the next passed argument,
+// the rest of passed arguments go after the bound ones
+func Partial(fn Variadic, bound ...Object) Variadic {
+	if fn == nil {
+		return nil
+	}
+
+	return func(args ...Object) Object {
+		result := make([]Object, 0, len(bound)+len(args))
+		position := 0
+
+		for _, arg := range bound {
+			if arg == Placeholder && position < len(args) {
+				arg = args[position]
+				position++
+			}
+			result = append(result, arg)
+		}
+
+		return fn(append(result, args[position:]...)...)
+	}
+}
+
+/* private methods */
+// placeholder is the type of Placeholder value
+type placeholder struct{}
+
+// memoCache is the goroutine-safe cache, which evicts the least recently used entries
+// when it's bounded by positive size
+type memoCache struct {
+	mutex   sync.Mutex
+	size    int
+	order   *list.List
+	entries map[Object]*list.Element
+}
+
+// memoEntry is the cached result with its key
+type memoEntry struct {
+	key    Object
+	result Object
+}
+
+func newMemoCache(size int) *memoCache {
+	return &memoCache{size: size, order: list.New(), entries: make(map[Object]*list.Element)}
+}
+
+func (cache *memoCache) get(key Object) (Object, bool) {
+	if !isHashable(reflect.ValueOf(key)) {
+		return nil, false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	return element.Value.(*memoEntry).result, true
+}
+
+func (cache *memoCache) put(key, result Object) {
+	if !isHashable(reflect.ValueOf(key)) {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		element.Value.(*memoEntry).result = result
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&memoEntry{key: key, result: result})
+
+	if cache.size > 0 && cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*memoEntry).key)
+	}
+}
+
+// isHashable returns true if the value can be the map key, e. g. it doesn't contain slices, maps or functions
+func isHashable(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Interface:
+		return value.IsNil() || isHashable(value.Elem())
+	case reflect.Array:
+		for index := 0; index < value.Len(); index++ {
+			if !isHashable(value.Index(index)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for index := 0; index < value.NumField(); index++ {
+			if !isHashable(value.Field(index)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,284 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"fmt"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"sync"
+	"testing"
+)
+
+func TestFunctions(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	evenPredicate := func(cur, _, _ Object) bool { return cur.(int)%2 == 0 }
+
+	g.Describe("#Negate()", func() {
+		g.It("Should return Predicate with opposite result", func() {
+			g.Assert(Filter(Seq{1, 2, 3, 4}, Negate(evenPredicate))).Equal(Seq{1, 3})
+			g.Assert(Negate(nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#NegateComparator()", func() {
+		g.It("Should return Comparator with opposite order", func() {
+			g.Assert(SortBy(Seq{2, 3, 1}, NegateComparator(intComparator))).Equal(Seq{3, 2, 1})
+			g.Assert(NegateComparator(nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Memoize()", func() {
+		g.It("Should call Function once for every key", func() {
+			calls := 0
+			square := Memoize(func(arg Object) Object {
+				calls++
+				return arg.(int) * arg.(int)
+			}, nil, 0)
+
+			g.Assert(square(3)).Equal(9)
+			g.Assert(square(3)).Equal(9)
+			g.Assert(square(4)).Equal(16)
+			g.Assert(calls).Equal(2)
+			g.Assert(Memoize(nil, nil, 0) == nil).IsTrue()
+		})
+
+		g.It("Should use key Function for not comparable arguments", func() {
+			calls := 0
+			sum := Memoize(func(arg Object) Object {
+				calls++
+				return len(arg.([]int))
+			}, func(arg Object) Object { return fmt.Sprint(arg) }, 0)
+
+			g.Assert(sum([]int{1, 2})).Equal(2)
+			g.Assert(sum([]int{1, 2})).Equal(2)
+			g.Assert(calls).Equal(1)
+		})
+
+		g.It("Should evict the least recently used results", func() {
+			calls := 0
+			identity := Memoize(func(arg Object) Object {
+				calls++
+				return arg
+			}, nil, 2)
+
+			identity(1)
+			identity(2)
+			identity(1)
+			identity(3)
+			g.Assert(calls).Equal(3)
+
+			identity(1)
+			g.Assert(calls).Equal(3)
+
+			identity(2)
+			g.Assert(calls).Equal(4)
+		})
+
+		g.It("Should be safe to call from many goroutines", func() {
+			double := Memoize(func(arg Object) Object { return arg.(int) * 2 }, nil, 8)
+			var group sync.WaitGroup
+
+			for i := 0; i < 16; i++ {
+				group.Add(1)
+				go func(i int) {
+					defer group.Done()
+					for j := 0; j < 100; j++ {
+						if double(j%20) != (j%20)*2 {
+							panic("wrong result")
+						}
+					}
+				}(i)
+			}
+
+			group.Wait()
+		})
+	})
+
+	g.Describe("#MemoizeCallback() and #MemoizePredicate()", func() {
+		g.It("Should cache results by current value or key Callback", func() {
+			calls := 0
+			slow := MemoizeCallback(func(cur, _, _ Object) Object {
+				calls++
+				return cur.(int) + 1
+			}, nil, 0)
+			even := MemoizePredicate(func(cur, _, _ Object) bool {
+				calls++
+				return cur.(int)%2 == 0
+			}, func(cur, _, _ Object) Object { return cur.(int) % 2 }, 0)
+
+			g.Assert(Map(Seq{1, 1, 2, 1}, slow)).Equal(Seq{2, 2, 3, 2})
+			g.Assert(calls).Equal(2)
+			g.Assert(Filter(Seq{1, 2, 3, 4, 5}, even)).Equal(Seq{2, 4})
+			g.Assert(calls).Equal(4)
+			g.Assert(MemoizeCallback(nil, nil, 0) == nil).IsTrue()
+			g.Assert(MemoizePredicate(nil, nil, 0) == nil).IsTrue()
+		})
+
+		g.It("Should not cache results for the values, which are not comparable", func() {
+			calls := 0
+			length := MemoizeCallback(func(cur, _, _ Object) Object {
+				calls++
+				return len(cur.(Seq))
+			}, nil, 0)
+
+			g.Assert(Map(Seq{Seq{1}, Seq{1, 2}, Seq{1}}, length)).Equal(Seq{1, 2, 1})
+			g.Assert(calls).Equal(3)
+
+			byLength := MemoizeComparator(func(left, right Object) int {
+				calls++
+				return len(left.(Seq)) - len(right.(Seq))
+			}, nil, 0)
+
+			g.Assert(byLength(Seq{1}, Seq{1, 2}) < 0).IsTrue()
+			g.Assert(calls).Equal(4)
+		})
+	})
+
+	g.Describe("#MemoizeComparator()", func() {
+		g.It("Should cache results by pair of values", func() {
+			calls := 0
+			cmp := MemoizeComparator(func(l, r Object) int {
+				calls++
+				return intComparator(l, r)
+			}, nil, 0)
+
+			g.Assert(cmp(1, 2)).Equal(-1)
+			g.Assert(cmp(1, 2)).Equal(-1)
+			g.Assert(cmp(2, 1)).Equal(1)
+			g.Assert(calls).Equal(2)
+			g.Assert(MemoizeComparator(nil, nil, 0) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Once() and its variants", func() {
+		g.It("Should call function only once", func() {
+			calls := 0
+			init := Once(func(arg Object) Object {
+				calls++
+				return arg
+			})
+			action := OnceAction(func(_, _, _ Object) { calls++ })
+
+			g.Assert(init(1)).Equal(1)
+			g.Assert(init(2)).Equal(1)
+			Each(Seq{1, 2, 3}, action)
+			g.Assert(calls).Equal(2)
+			g.Assert(Once(nil) == nil).IsTrue()
+			g.Assert(OnceAction(nil) == nil).IsTrue()
+		})
+
+		g.It("Should call Callback and Predicate only once", func() {
+			first := OnceCallback(func(cur, _, _ Object) Object { return cur })
+			isFirstEven := OncePredicate(func(cur, _, _ Object) bool { return cur.(int)%2 == 0 })
+
+			g.Assert(Map(Seq{1, 2, 3}, first)).Equal(Seq{1, 1, 1})
+			g.Assert(Filter(Seq{2, 3, 5}, isFirstEven)).Equal(Seq{2, 3, 5})
+			g.Assert(OnceCallback(nil) == nil).IsTrue()
+			g.Assert(OncePredicate(nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#After() and #Before() and their variants", func() {
+		g.It("Should call Action only after or before n calls", func() {
+			after := NewSeq(0)
+			before := NewSeq(0)
+
+			Each(Seq{1, 2, 3, 4}, After(3, func(cur, _, _ Object) { after = append(after, cur) }))
+			Each(Seq{1, 2, 3, 4}, Before(3, func(cur, _, _ Object) { before = append(before, cur) }))
+
+			g.Assert(after).Equal(Seq{3, 4})
+			g.Assert(before).Equal(Seq{1, 2})
+			g.Assert(After(1, nil) == nil).IsTrue()
+			g.Assert(Before(1, nil) == nil).IsTrue()
+		})
+
+		g.It("Should call Function, Callback and Predicate only after or before n calls", func() {
+			double := func(arg Object) Object { return arg.(int) * 2 }
+			doubleCb := func(cur, _, _ Object) Object { return cur.(int) * 2 }
+			isOdd := func(cur, _, _ Object) bool { return cur.(int)%2 != 0 }
+
+			g.Assert(Map(Seq{1, 2, 3, 4}, AfterCallback(3, doubleCb))).Equal(Seq{nil, nil, 6, 8})
+			g.Assert(Map(Seq{1, 2, 3, 4}, BeforeCallback(3, doubleCb))).Equal(Seq{2, 4, 4, 4})
+			g.Assert(Filter(Seq{1, 3, 5, 6}, AfterPredicate(2, isOdd))).Equal(Seq{3, 5})
+			g.Assert(Filter(Seq{1, 3, 5, 6}, BeforePredicate(3, isOdd))).Equal(Seq{1, 3, 5, 6})
+			g.Assert(Filter(Seq{1, 3}, BeforePredicate(1, isOdd))).Equal(Seq{})
+
+			after, before := AfterFunction(2, double), BeforeFunction(2, double)
+			g.Assert(Seq{after(1), after(2), before(3), before(4)}).Equal(Seq{nil, 4, 6, 6})
+
+			g.Assert(AfterFunction(1, nil) == nil).IsTrue()
+			g.Assert(AfterCallback(1, nil) == nil).IsTrue()
+			g.Assert(AfterPredicate(1, nil) == nil).IsTrue()
+			g.Assert(BeforeFunction(1, nil) == nil).IsTrue()
+			g.Assert(BeforeCallback(1, nil) == nil).IsTrue()
+			g.Assert(BeforePredicate(1, nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Compose() and its variants", func() {
+		g.It("Should call functions from the last one", func() {
+			inc := func(arg Object) Object { return arg.(int) + 1 }
+			double := func(arg Object) Object { return arg.(int) * 2 }
+			incCb := func(cur, _, _ Object) Object { return cur.(int) + 1 }
+			indexCb := func(cur, index, _ Object) Object { return cur.(int) * index.(int) }
+
+			g.Assert(Compose(inc, double)(5)).Equal(11)
+			g.Assert(Compose(double, inc)(5)).Equal(12)
+			g.Assert(Compose()(5)).Equal(5)
+			g.Assert(Map(Seq{1, 1, 1}, ComposeCallback(incCb, indexCb))).Equal(Seq{1, 2, 3})
+			g.Assert(Map(Seq{1, 1}, ComposeCallback(nil, incCb))).Equal(Seq{2, 2})
+		})
+
+		g.It("Should compose Predicate and Comparator with functions", func() {
+			incCb := func(cur, _, _ Object) Object { return cur.(int) + 1 }
+			isEven := func(cur, _, _ Object) bool { return cur.(int)%2 == 0 }
+			length := func(arg Object) Object { return len(arg.(string)) }
+
+			g.Assert(Filter(Seq{1, 2, 3}, ComposePredicate(isEven, incCb))).Equal(Seq{1, 3})
+			g.Assert(SortBy(Seq{"ccc", "a", "bb"}, ComposeComparator(Compare, length))).Equal(Seq{"a", "bb", "ccc"})
+			g.Assert(ComposePredicate(nil, incCb) == nil).IsTrue()
+			g.Assert(ComposeComparator(nil, length) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Partial()", func() {
+		g.It("Should call Variadic with bound arguments", func() {
+			join := func(args ...Object) Object {
+				result := ""
+				for _, arg := range args {
+					result += fmt.Sprintf("%v;", arg)
+				}
+				return result
+			}
+
+			g.Assert(Partial(join, "a", "b")("c")).Equal("a;b;c;")
+			g.Assert(Partial(join, Placeholder, "b")("a", "c")).Equal("a;b;c;")
+			g.Assert(Partial(join, Placeholder, "b")()).Equal("{};b;")
+			g.Assert(Partial(join)("x")).Equal("x;")
+			g.Assert(Partial(nil, 1) == nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,160 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import "math"
+
+// Range returns slice of ints from start up to stop (exclusive), incremented by step,
+// zero step means 1 or -1 depending on direction, if step goes away from stop, slice is empty,
+// it panics if slice would be longer than math.MaxInt, take the larger step or split the range to avoid it
+func Range(start, stop, step int) Seq {
+	if step == 0 {
+		step = 1
+		if stop < start {
+			step = -1
+		}
+	}
+	if (step > 0 && start >= stop) || (step < 0 && start <= stop) {
+		return Seq{}
+	}
+
+	// the distance is counted in uint64, because stop - start overflows int for the far bounds
+	span, stride := uint64(stop)-uint64(start), uint64(step)
+	if step < 0 {
+		span, stride = uint64(start)-uint64(stop), -uint64(step)
+	}
+
+	length := span / stride
+	if span%stride != 0 {
+		length++
+	}
+	if length > math.MaxInt {
+		panic("ugo: Range is longer than math.MaxInt")
+	}
+
+	result := NewSeq(int(length))
+	for i := range result {
+		result[i] = start + i*step
+	}
+
+	return result
+}
+
+// RangeFloat returns slice of float64 from start up to stop (exclusive), incremented by step (see #Range),
+// slice is empty if any of the arguments is NaN or the range is infinite
+func RangeFloat(start, stop, step float64) Seq {
+	if math.IsNaN(start) || math.IsNaN(stop) || math.IsNaN(step) || math.IsInf(step, 0) {
+		return Seq{}
+	}
+	if step == 0 {
+		step = 1
+		if stop < start {
+			step = -1
+		}
+	}
+	if (step > 0 && start >= stop) || (step < 0 && start <= stop) {
+		return Seq{}
+	}
+
+	length := math.Ceil((stop - start) / step)
+	if math.IsInf(length, 0) {
+		return Seq{}
+	}
+	if length > math.MaxInt {
+		panic("ugo: RangeFloat is longer than math.MaxInt")
+	}
+
+	result := NewSeq(int(length))
+	for i := range result {
+		result[i] = start + float64(i)*step
+	}
+
+	return result
+}
+
+// Times returns slice of n results of Callback, which gets the index as current value and key,
+// if Callback is not passed, slice contains indexes
+func Times(n int, cb Callback) Seq {
+	if n <= 0 {
+		return Seq{}
+	}
+
+	result := NewSeq(n)
+	for i := range result {
+		if cb == nil {
+			result[i] = i
+		} else {
+			result[i] = cb(i, i, result)
+		}
+	}
+
+	return result
+}
+
+// Repeat returns slice, which contains the same value n times
+func Repeat(value Object, n int) Seq {
+	if n <= 0 {
+		return Seq{}
+	}
+
+	result := NewSeq(n)
+	for i := range result {
+		result[i] = value
+	}
+
+	return result
+}
+
+// Iterate returns slice of n elements, the first one is seed,
+// and every next one is the result of Callback, called with the previous one
+func Iterate(seed Object, cb Callback, n int) Seq {
+	if n <= 0 {
+		return Seq{}
+	}
+	if cb == nil {
+		return Repeat(seed, n)
+	}
+
+	result := NewSeq(n)
+	result[0] = seed
+
+	for i := 1; i < n; i++ {
+		result[i] = cb(result[i-1], i, result)
+	}
+
+	return result
+}
+
+// Cycle returns slice of given length, which repeats elements of given slice over and over
+func Cycle(seq Seq, length int) Seq {
+	if IsEmpty(seq) || length <= 0 {
+		return Seq{}
+	}
+
+	result := NewSeq(length)
+	for i := range result {
+		result[i] = seq[i%len(seq)]
+	}
+
+	return result
+}
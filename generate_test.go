@@ -0,0 +1,137 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math"
+	"testing"
+)
+
+func TestGenerators(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#Range()", func() {
+		g.It("Should return ints from start to stop with given step", func() {
+			g.Assert(Range(0, 5, 1)).Equal(Seq{0, 1, 2, 3, 4})
+			g.Assert(Range(1, 11, 3)).Equal(Seq{1, 4, 7, 10})
+			g.Assert(Range(0, -10, -5)).Equal(Seq{0, -5})
+			g.Assert(Range(5, 0, -2)).Equal(Seq{5, 3, 1})
+			g.Assert(Range(3, 0, 0)).Equal(Seq{3, 2, 1})
+			g.Assert(Range(0, 3, 0)).Equal(Seq{0, 1, 2})
+		})
+
+		g.It("Should return empty Seq if step goes away from stop", func() {
+			g.Assert(Range(0, 5, -1)).Equal(Seq{})
+			g.Assert(Range(5, 0, 1)).Equal(Seq{})
+			g.Assert(Range(2, 2, 1)).Equal(Seq{})
+		})
+		g.It("Should not overflow for the far bounds", func() {
+			start, stop, step := math.MaxInt-7, math.MinInt+8, -(math.MaxInt / 9)
+			res := Range(start, stop, step)
+
+			g.Assert(len(res)).Equal(18)
+			g.Assert(res[0]).Equal(start)
+			g.Assert(res[17]).Equal(start + 17*step)
+			g.Assert(res[17].(int)+step <= stop).IsTrue()
+
+			g.Assert(Range(math.MinInt, math.MaxInt, math.MaxInt)).Equal(Seq{math.MinInt, -1, math.MaxInt - 1})
+		})
+
+		g.It("Should panic if Seq would be longer than int holds", func() {
+			defer func() { g.Assert(recover()).Equal("ugo: Range is longer than math.MaxInt") }()
+
+			Range(math.MinInt, math.MaxInt, 1)
+		})
+	})
+
+	g.Describe("#RangeFloat()", func() {
+		g.It("Should return floats from start to stop with given step", func() {
+			g.Assert(RangeFloat(0, 1, 0.25)).Equal(Seq{0.0, 0.25, 0.5, 0.75})
+			g.Assert(RangeFloat(1, 0, -0.5)).Equal(Seq{1.0, 0.5})
+			g.Assert(RangeFloat(0, 2.5, 0)).Equal(Seq{0.0, 1.0, 2.0})
+			g.Assert(len(RangeFloat(0, 1, 0.1))).Equal(10)
+		})
+
+		g.It("Should return empty Seq for wrong arguments", func() {
+			g.Assert(RangeFloat(0, 1, -0.5)).Equal(Seq{})
+			g.Assert(RangeFloat(math.NaN(), 1, 1)).Equal(Seq{})
+			g.Assert(RangeFloat(0, math.Inf(1), 1)).Equal(Seq{})
+			g.Assert(RangeFloat(0, 1, math.Inf(1))).Equal(Seq{})
+		})
+
+		g.It("Should panic if Seq would be longer than int holds", func() {
+			defer func() { g.Assert(recover()).Equal("ugo: RangeFloat is longer than math.MaxInt") }()
+
+			RangeFloat(0, 1e300, 1)
+		})
+	})
+
+	g.Describe("#Times()", func() {
+		g.It("Should return results of Callback called n times", func() {
+			g.Assert(Times(4, func(cur, _, _ Object) Object { return cur.(int) * cur.(int) })).Equal(Seq{0, 1, 4, 9})
+			g.Assert(Times(3, nil)).Equal(Seq{0, 1, 2})
+			g.Assert(Times(0, nil)).Equal(Seq{})
+			g.Assert(Times(-1, nil)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Repeat()", func() {
+		g.It("Should return Seq with the same value n times", func() {
+			g.Assert(Repeat("x", 3)).Equal(Seq{"x", "x", "x"})
+			g.Assert(Repeat(nil, 2)).Equal(Seq{nil, nil})
+			g.Assert(Repeat(1, 0)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Iterate()", func() {
+		g.It("Should return seed and results of Callback called with previous value", func() {
+			double := func(cur, _, _ Object) Object { return cur.(int) * 2 }
+
+			g.Assert(Iterate(1, double, 5)).Equal(Seq{1, 2, 4, 8, 16})
+			g.Assert(Iterate(1, double, 1)).Equal(Seq{1})
+			g.Assert(Iterate(1, nil, 2)).Equal(Seq{1, 1})
+			g.Assert(Iterate(1, double, 0)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Cycle()", func() {
+		g.It("Should return Seq of given length, repeating elements", func() {
+			g.Assert(Cycle(Seq{1, 2, 3}, 7)).Equal(Seq{1, 2, 3, 1, 2, 3, 1})
+			g.Assert(Cycle(Seq{1, 2, 3}, 2)).Equal(Seq{1, 2})
+			g.Assert(Cycle(nil, 3)).Equal(Seq{})
+			g.Assert(Cycle(Seq{1}, -1)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("Chaining", func() {
+		g.It("Should be usable as a Chain source", func() {
+			sum := func(memo, cur, _, _ Object) Object { return memo.(int) + cur.(int) }
+			odd := func(cur, _, _ Object) bool { return cur.(int)%2 != 0 }
+
+			g.Assert(Chain(Range(0, 10, 1)).Filter(odd).Reduce(sum, 0).Value()).Equal(25)
+			g.Assert(Chain(Times(3, nil)).Concat(Repeat(7, 2)).Value()).Equal(Seq{0, 1, 2, 7, 7})
+		})
+	})
+}
@@ -0,0 +1,5 @@
+module github.com/alxrm/ugo
+
+go 1.23
+
+require github.com/franela/goblin v0.0.0-20211003143422-0a4f594942bf
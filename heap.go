@@ -0,0 +1,246 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// Heap is the special struct, which keeps its least element on top,
+// elements are compared by the Comparator it was created with,
+// the zero Heap is the empty one, which compares elements by #Compare
+type Heap struct {
+	seq Seq        // seq is the binary heap storage
+	cb  Comparator // cb is the Comparator, which defines the order
+}
+
+// NewHeap creates a new empty Heap, returns nil if Comparator is not passed
+func NewHeap(cb Comparator) *Heap {
+	if cb == nil {
+		return nil
+	}
+	return &Heap{seq: NewSeq(0), cb: cb}
+}
+
+// Heapify creates a new Heap from the copy of given slice in O(n) operations,
+// returns nil if Comparator is not passed
+func Heapify(seq Seq, cb Comparator) *Heap {
+	if cb == nil {
+		return nil
+	}
+
+	heap := &Heap{seq: NewSeq(len(seq)), cb: cb}
+	copy(heap.seq, seq)
+
+	for i := len(heap.seq)/2 - 1; i >= 0; i-- {
+		heap.down(i)
+	}
+
+	return heap
+}
+
+// Len returns the count of elements in Heap
+func (heap *Heap) Len() int {
+	return len(heap.seq)
+}
+
+// Push puts an element into the Heap
+func (heap *Heap) Push(target Object) {
+	heap.seq = append(heap.seq, target)
+	heap.up(len(heap.seq) - 1)
+}
+
+// Pop takes the least element out of the Heap, returns nil if Heap is empty
+func (heap *Heap) Pop() Object {
+	last := len(heap.seq) - 1
+	if last < 0 {
+		return nil
+	}
+
+	top := heap.seq[0]
+	heap.seq[0] = heap.seq[last]
+	heap.seq[last] = nil
+	heap.seq = heap.seq[:last]
+	heap.down(0)
+
+	return top
+}
+
+// Peek returns the least element without taking it out, returns nil if Heap is empty
+func (heap *Heap) Peek() Object {
+	if len(heap.seq) == 0 {
+		return nil
+	}
+	return heap.seq[0]
+}
+
+// Fix restores the order after the element on given position has changed its value
+func (heap *Heap) Fix(position int) {
+	if position < 0 || position >= len(heap.seq) {
+		return
+	}
+	if !heap.down(position) {
+		heap.up(position)
+	}
+}
+
+// Seq returns the copy of Heap elements in the order they're stored
+func (heap *Heap) Seq() Seq {
+	copied := NewSeq(len(heap.seq))
+	copy(copied, heap.seq)
+	return copied
+}
+
+// TopK returns k largest elements of slice, sorted from the largest one
+func TopK(seq Seq, k int, cb Comparator) Seq {
+	if cb == nil {
+		return Seq{}
+	}
+	return createSelection(seq, k, func(l, r Object) int { return cb(r, l) })
+}
+
+// BottomK returns k least elements of slice, sorted from the least one
+func BottomK(seq Seq, k int, cb Comparator) Seq {
+	if cb == nil {
+		return Seq{}
+	}
+	return createSelection(seq, k, cb)
+}
+
+// MergeSorted returns the slice, which contains elements of all the given sorted slices
+// and is sorted by the same Comparator, equal elements keep the order of the slices
+func MergeSorted(cb Comparator, seqs ...Seq) Seq {
+	if cb == nil {
+		return Seq{}
+	}
+
+	length := 0
+	cursors := NewSeq(0)
+
+	for index, seq := range seqs {
+		length += len(seq)
+		if len(seq) != 0 {
+			cursors = append(cursors, &mergeCursor{seq: seq, order: index})
+		}
+	}
+
+	heap := Heapify(cursors, func(l, r Object) int {
+		left, right := l.(*mergeCursor), r.(*mergeCursor)
+		if diff := cb(left.seq[left.position], right.seq[right.position]); diff != 0 {
+			return diff
+		}
+		return left.order - right.order
+	})
+	result := make(Seq, 0, length)
+
+	for heap.Len() != 0 {
+		cursor := heap.Peek().(*mergeCursor)
+		result = append(result, cursor.seq[cursor.position])
+
+		if cursor.position++; cursor.position == len(cursor.seq) {
+			heap.Pop()
+		} else {
+			heap.Fix(0)
+		}
+	}
+
+	return result
+}
+
+/* private methods */
+// comparator returns the Comparator of Heap, setting #Compare for the zero Heap
+func (heap *Heap) comparator() Comparator {
+	if heap.cb == nil {
+		heap.cb = Compare
+	}
+	return heap.cb
+}
+
+// mergeCursor is the position in one of the slices, merged in #MergeSorted
+type mergeCursor struct {
+	seq      Seq
+	order    int
+	position int
+}
+
+// up moves the element on given position towards the top, while it's less than its parent
+func (heap *Heap) up(position int) {
+	cb := heap.comparator()
+
+	for position > 0 {
+		parent := (position - 1) / 2
+		if cb(heap.seq[position], heap.seq[parent]) >= 0 {
+			break
+		}
+		heap.seq[position], heap.seq[parent] = heap.seq[parent], heap.seq[position]
+		position = parent
+	}
+}
+
+// down moves the element on given position towards the bottom, while it's larger than its children,
+// returns true if the element has been moved
+func (heap *Heap) down(position int) bool {
+	start := position
+	length := len(heap.seq)
+	cb := heap.comparator()
+
+	for {
+		least := 2*position + 1
+		if least >= length {
+			break
+		}
+		if right := least + 1; right < length && cb(heap.seq[right], heap.seq[least]) < 0 {
+			least = right
+		}
+		if cb(heap.seq[least], heap.seq[position]) >= 0 {
+			break
+		}
+		heap.seq[position], heap.seq[least] = heap.seq[least], heap.seq[position]
+		position = least
+	}
+
+	return position > start
+}
+
+// createSelection returns k least elements by Comparator, sorted from the least one,
+// it keeps k elements in a Heap, which has the largest one on top
+func createSelection(seq Seq, k int, cb Comparator) Seq {
+	if k <= 0 || IsEmpty(seq) {
+		return Seq{}
+	}
+	if k > len(seq) {
+		k = len(seq)
+	}
+
+	heap := Heapify(seq[:k], func(l, r Object) int { return cb(r, l) })
+
+	for _, value := range seq[k:] {
+		if cb(value, heap.Peek()) < 0 {
+			heap.seq[0] = value
+			heap.down(0)
+		}
+	}
+
+	result := NewSeq(k)
+	for i := k - 1; i >= 0; i-- {
+		result[i] = heap.Pop()
+	}
+
+	return result
+}
@@ -0,0 +1,152 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestHeap(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+
+	g.Describe("#NewHeap()", func() {
+		g.It("Should create empty Heap", func() {
+			heap := NewHeap(intComparator)
+
+			g.Assert(heap.Len()).Equal(0)
+			g.Assert(heap.Peek()).Equal(nil)
+			g.Assert(heap.Pop()).Equal(nil)
+			g.Assert(NewHeap(nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Push() and #Pop()", func() {
+		g.It("Should pop elements from the least one", func() {
+			heap := NewHeap(intComparator)
+			result := NewSeq(0)
+
+			for _, value := range []int{5, 1, 9, 3, 3, 7, -2} {
+				heap.Push(value)
+			}
+
+			g.Assert(heap.Len()).Equal(7)
+			g.Assert(heap.Peek()).Equal(-2)
+
+			for heap.Len() != 0 {
+				result = append(result, heap.Pop())
+			}
+
+			g.Assert(result).Equal(Seq{-2, 1, 3, 3, 5, 7, 9})
+		})
+
+		g.It("Should work with the zero Heap", func() {
+			heap := &Heap{}
+
+			g.Assert(heap.Pop() == nil).IsTrue()
+
+			heap.Push(3)
+			heap.Push(1.5)
+			heap.Push(2)
+
+			g.Assert(heap.Pop()).Equal(1.5)
+			g.Assert(heap.Pop()).Equal(2)
+			g.Assert(heap.Len()).Equal(1)
+		})
+	})
+
+	g.Describe("#Heapify()", func() {
+		g.It("Should create Heap from the copy of Seq", func() {
+			inSeq := Seq{8, 3, 6, 1, 9, 2}
+			heap := Heapify(inSeq, intComparator)
+
+			g.Assert(heap.Pop()).Equal(1)
+			g.Assert(heap.Pop()).Equal(2)
+			g.Assert(heap.Len()).Equal(4)
+			g.Assert(inSeq).Equal(Seq{8, 3, 6, 1, 9, 2})
+			g.Assert(Heapify(nil, intComparator).Len()).Equal(0)
+			g.Assert(Heapify(inSeq, nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#Fix()", func() {
+		g.It("Should restore the order after element has changed", func() {
+			type task struct{ priority int }
+			taskComparator := func(l, r Object) int { return l.(*task).priority - r.(*task).priority }
+
+			tasks := Seq{&task{5}, &task{2}, &task{8}}
+			heap := Heapify(tasks, taskComparator)
+
+			tasks[1].(*task).priority = 10
+			heap.Fix(FindIndex(heap.Seq(), func(cur, _, _ Object) bool { return cur == tasks[1] }))
+			heap.Fix(-1)
+			heap.Fix(100)
+
+			g.Assert(heap.Pop().(*task).priority).Equal(5)
+			g.Assert(heap.Pop().(*task).priority).Equal(8)
+			g.Assert(heap.Pop().(*task).priority).Equal(10)
+		})
+	})
+
+	g.Describe("#TopK()", func() {
+		g.It("Should return k largest elements", func() {
+			inSeq := Seq{4, 17, 2, 9, 17, 0, 5}
+
+			g.Assert(TopK(inSeq, 3, intComparator)).Equal(Seq{17, 17, 9})
+			g.Assert(TopK(inSeq, 10, intComparator)).Equal(Seq{17, 17, 9, 5, 4, 2, 0})
+			g.Assert(TopK(inSeq, 0, intComparator)).Equal(Seq{})
+			g.Assert(TopK(nil, 2, intComparator)).Equal(Seq{})
+			g.Assert(TopK(inSeq, 2, nil)).Equal(Seq{})
+			g.Assert(inSeq).Equal(Seq{4, 17, 2, 9, 17, 0, 5})
+		})
+	})
+
+	g.Describe("#BottomK()", func() {
+		g.It("Should return k least elements", func() {
+			inSeq := Seq{4, 17, 2, 9, 17, 0, 5}
+
+			g.Assert(BottomK(inSeq, 3, intComparator)).Equal(Seq{0, 2, 4})
+			g.Assert(BottomK(inSeq, 1, intComparator)).Equal(Seq{0})
+			g.Assert(BottomK(inSeq, -1, intComparator)).Equal(Seq{})
+			g.Assert(BottomK(inSeq, 2, nil)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#MergeSorted()", func() {
+		g.It("Should merge sorted Seqs into one sorted Seq", func() {
+			g.Assert(MergeSorted(intComparator, Seq{1, 4, 7}, Seq{2, 5, 8}, Seq{0, 3, 6, 9})).Equal(Seq{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+			g.Assert(MergeSorted(intComparator, Seq{1, 1}, nil, Seq{}, Seq{1, 2})).Equal(Seq{1, 1, 1, 2})
+			g.Assert(MergeSorted(intComparator)).Equal(Seq{})
+			g.Assert(MergeSorted(nil, Seq{1})).Equal(Seq{})
+		})
+
+		g.It("Should keep the order of Seqs for equal elements", func() {
+			tensComparator := func(l, r Object) int { return l.(int)/10 - r.(int)/10 }
+
+			g.Assert(MergeSorted(tensComparator, Seq{11, 25}, Seq{3, 12, 21})).Equal(Seq{3, 11, 12, 25, 21})
+		})
+	})
+}
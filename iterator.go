@@ -0,0 +1,128 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// IndexOfIterator returns the position of the first element of Iterator, which equals to passed one(target),
+// or -1 if there is no such element (see #IndexOf), it stops the Iterator as soon as the element is found
+func IndexOfIterator(it Iterator, target Object, cb Comparator) int {
+	if cb == nil {
+		return -1
+	}
+
+	found := -1
+	eachIterator(it, func(cur Object, index int) bool {
+		if cb(cur, target) == equal {
+			found = index
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// FindIterator returns the first element of Iterator, passed the predicate check (see #Find),
+// Predicate gets the position of element and the Iterator itself
+func FindIterator(it Iterator, cb Predicate) Object {
+	if cb == nil {
+		return nil
+	}
+
+	var found Object
+	eachIterator(it, func(cur Object, index int) bool {
+		if cb(cur, index, it) {
+			found = cur
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// ReduceIterator combines the elements of Iterator into one value (see #Reduce),
+// if initial value is nil, the first element is used instead of it
+func ReduceIterator(it Iterator, cb Collector, initial Object) Object {
+	if cb == nil {
+		return nil
+	}
+
+	memo := initial
+	eachIterator(it, func(cur Object, index int) bool {
+		if index == 0 && initial == nil {
+			memo = cur
+		} else {
+			memo = cb(memo, cur, index, it)
+		}
+		return true
+	})
+
+	return memo
+}
+
+// SomeIterator returns true if at least one element of Iterator passed the predicate check (see #Some)
+func SomeIterator(it Iterator, cb Predicate) bool {
+	if cb == nil {
+		return false
+	}
+
+	found := false
+	eachIterator(it, func(cur Object, index int) bool {
+		found = cb(cur, index, it)
+		return !found
+	})
+
+	return found
+}
+
+// EveryIterator returns true if every element of Iterator has passed the predicate test (see #Every),
+// it returns false for the empty Iterator just like #Every does
+func EveryIterator(it Iterator, cb Predicate) bool {
+	if cb == nil {
+		return false
+	}
+
+	passed, empty := true, true
+	eachIterator(it, func(cur Object, index int) bool {
+		empty = false
+		passed = cb(cur, index, it)
+		return passed
+	})
+
+	return passed && !empty
+}
+
+/* private methods */
+// eachIterator calls cb with each element of Iterator and its position, until cb returns false
+func eachIterator(it Iterator, cb func(cur Object, index int) bool) {
+	if it == nil {
+		return
+	}
+
+	index := 0
+	it(func(current Object) bool {
+		next := cb(current, index)
+		index++
+		return next
+	})
+}
@@ -0,0 +1,99 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	g := Goblin(t)
+
+	isEven := func(cur, _, _ Object) bool { return cur.(int)%2 == 0 }
+	sum := func(memo, cur, _, _ Object) Object { return memo.(int) + cur.(int) }
+
+	// counted returns Iterator over Vector and the pointer to the count of elements it has passed
+	counted := func(seq Seq) (Iterator, *int) {
+		passed := 0
+		it := NewVector(seq).Iterator()
+		return func(yield func(current Object) bool) {
+			it(func(current Object) bool {
+				passed++
+				return yield(current)
+			})
+		}, &passed
+	}
+
+	g.Describe("#IndexOfIterator()", func() {
+		g.It("Should return the position of the first equal element and stop", func() {
+			it, passed := counted(Seq{5, 3, 8, 3, 1})
+
+			g.Assert(IndexOfIterator(it, 3, Compare)).Equal(1)
+			g.Assert(*passed).Equal(2)
+			g.Assert(IndexOfIterator(it, 4, Compare)).Equal(-1)
+			g.Assert(IndexOfIterator(it, 3, nil)).Equal(-1)
+			g.Assert(IndexOfIterator(nil, 3, Compare)).Equal(-1)
+		})
+	})
+
+	g.Describe("#FindIterator()", func() {
+		g.It("Should return the first passed element and stop", func() {
+			it, passed := counted(Seq{5, 3, 8, 4, 1})
+
+			g.Assert(FindIterator(it, isEven)).Equal(8)
+			g.Assert(*passed).Equal(3)
+			g.Assert(FindIterator(NewVector(Seq{1, 3}).Iterator(), isEven) == nil).IsTrue()
+			g.Assert(FindIterator(it, nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#ReduceIterator()", func() {
+		g.It("Should combine elements with and without initial value", func() {
+			it := NewVector(Seq{1, 2, 3, 4}).Iterator()
+
+			g.Assert(ReduceIterator(it, sum, nil)).Equal(10)
+			g.Assert(ReduceIterator(it, sum, 5)).Equal(15)
+			g.Assert(ReduceIterator(Vector{}.Iterator(), sum, 5)).Equal(5)
+			g.Assert(ReduceIterator(it, nil, 5) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#SomeIterator() and #EveryIterator()", func() {
+		g.It("Should check elements and stop as soon as the answer is known", func() {
+			it, passed := counted(Seq{2, 4, 5, 6})
+
+			g.Assert(SomeIterator(it, isEven)).IsTrue()
+			g.Assert(*passed).Equal(1)
+
+			g.Assert(EveryIterator(it, isEven)).IsFalse()
+			g.Assert(*passed).Equal(4)
+
+			g.Assert(EveryIterator(NewVector(Seq{2, 4}).Iterator(), isEven)).IsTrue()
+			g.Assert(SomeIterator(NewVector(Seq{1, 3}).Iterator(), isEven)).IsFalse()
+			g.Assert(EveryIterator(Vector{}.Iterator(), isEven)).IsFalse()
+			g.Assert(SomeIterator(nil, isEven)).IsFalse()
+		})
+	})
+}
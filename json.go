@@ -0,0 +1,385 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// NumberMode defines the way untyped JSON numbers are decoded into Seq
+type NumberMode int
+
+const (
+	NumberFloat64 NumberMode = iota // NumberFloat64 decodes every number as float64, just like encoding/json does
+	NumberInt64                     // NumberInt64 decodes integral numbers as int64 and the rest of them as float64
+	NumberJSON                      // NumberJSON decodes every number as json.Number
+)
+
+// JSONOptions defines the way Seq is encoded to and decoded from JSON
+type JSONOptions struct {
+	Typed   bool       // Typed wraps every element into the envelope with its type tag, so it's decoded with the same type
+	Numbers NumberMode // Numbers defines how numbers are decoded, when there is no type tag for them
+}
+
+// TypedSeq is the Seq, which is always encoded to JSON in typed envelope mode
+type TypedSeq Seq
+
+// typedSeqKey is the key of the typed envelope document
+const typedSeqKey = "$seq"
+
+// typedElement is the element of Seq in typed envelope mode
+type typedElement struct {
+	Type  string          `json:"$type"`
+	Value json.RawMessage `json:"$value,omitempty"`
+}
+
+// EncodeJSON returns the JSON representation of Seq, in typed envelope mode
+// every element is wrapped as {"$type": "int", "$value": 1}, nested Seq's and
+// map[string]Object values are wrapped as well, elements of other types cause an error
+func EncodeJSON(seq Seq, opts JSONOptions) ([]byte, error) {
+	if !opts.Typed {
+		return json.Marshal([]interface{}(seq))
+	}
+
+	elements, err := createTypedElements(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string][]typedElement{typedSeqKey: elements})
+}
+
+// DecodeJSON returns Seq, decoded from JSON array or typed envelope document,
+// numbers without type tags are decoded according to JSONOptions#Numbers
+func DecodeJSON(data []byte, opts JSONOptions) (Seq, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '{' {
+		var document map[string]json.RawMessage
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+
+		raw, ok := document[typedSeqKey]
+		if !ok || len(document) != 1 {
+			return nil, fmt.Errorf("ugo: JSON object is not a typed Seq, expected the only key %q", typedSeqKey)
+		}
+
+		return createTypedSeq(raw)
+	}
+
+	var values []interface{}
+	decoder := createNumberDecoder(data)
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("ugo: unexpected data after JSON value")
+	}
+
+	if values == nil {
+		return nil, nil
+	}
+
+	return createNumbers(values, opts.Numbers).([]interface{}), nil
+}
+
+// MarshalJSON encodes Seq as the plain JSON array
+func (seq Seq) MarshalJSON() ([]byte, error) {
+	return EncodeJSON(seq, JSONOptions{})
+}
+
+// UnmarshalJSON decodes Seq from the plain JSON array or typed envelope document,
+// integral numbers are decoded as int64 and the rest of them as float64 (see #NumberInt64),
+// so the integral floats come back as int64, use TypedSeq to keep the exact types
+func (seq *Seq) UnmarshalJSON(data []byte) error {
+	decoded, err := DecodeJSON(data, JSONOptions{Numbers: NumberInt64})
+	if err != nil {
+		return err
+	}
+
+	*seq = decoded
+	return nil
+}
+
+// MarshalJSON encodes TypedSeq as the typed envelope document
+func (seq TypedSeq) MarshalJSON() ([]byte, error) {
+	return EncodeJSON(Seq(seq), JSONOptions{Typed: true})
+}
+
+// UnmarshalJSON decodes TypedSeq from the plain JSON array or typed envelope document,
+// numbers of the plain array are decoded as they're decoded into Seq (see #Seq.UnmarshalJSON)
+func (seq *TypedSeq) UnmarshalJSON(data []byte) error {
+	decoded, err := DecodeJSON(data, JSONOptions{Numbers: NumberInt64})
+	if err != nil {
+		return err
+	}
+
+	*seq = TypedSeq(decoded)
+	return nil
+}
+
+/* private methods */
+func createNumberDecoder(data []byte) *json.Decoder {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder
+}
+
+// createNumbers converts every json.Number in decoded value according to NumberMode
+func createNumbers(value interface{}, mode NumberMode) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		return createNumber(typed, mode)
+	case []interface{}:
+		for index, cur := range typed {
+			typed[index] = createNumbers(cur, mode)
+		}
+	case map[string]interface{}:
+		for key, cur := range typed {
+			typed[key] = createNumbers(cur, mode)
+		}
+	}
+
+	return value
+}
+
+func createNumber(number json.Number, mode NumberMode) interface{} {
+	switch mode {
+	case NumberJSON:
+		return number
+	case NumberInt64:
+		if integral, err := number.Int64(); err == nil {
+			return integral
+		}
+	}
+
+	float, _ := number.Float64()
+	return float
+}
+
+func createTypedElements(seq Seq) ([]typedElement, error) {
+	elements := make([]typedElement, len(seq))
+
+	for index, cur := range seq {
+		element, err := createTypedElement(cur)
+		if err != nil {
+			return nil, fmt.Errorf("ugo: element %d: %v", index, err)
+		}
+
+		elements[index] = element
+	}
+
+	return elements, nil
+}
+
+func createTypedElement(value Object) (typedElement, error) {
+	var tag string
+	var encoded interface{} = value
+
+	switch typed := value.(type) {
+	case nil:
+		return typedElement{Type: "nil"}, nil
+	case bool:
+		tag = "bool"
+	case string:
+		tag = "string"
+	case int:
+		tag = "int"
+	case int8:
+		tag = "int8"
+	case int16:
+		tag = "int16"
+	case int32:
+		tag = "int32"
+	case int64:
+		tag = "int64"
+	case uint:
+		tag = "uint"
+	case uint8:
+		tag = "uint8"
+	case uint16:
+		tag = "uint16"
+	case uint32:
+		tag = "uint32"
+	case uint64:
+		tag = "uint64"
+	case float32:
+		tag = "float32"
+	case float64:
+		tag = "float64"
+	case json.Number:
+		tag = "number"
+	case Seq:
+		elements, err := createTypedElements(typed)
+		if err != nil {
+			return typedElement{}, err
+		}
+		tag, encoded = "seq", elements
+	case []interface{}:
+		elements, err := createTypedElements(typed)
+		if err != nil {
+			return typedElement{}, err
+		}
+		tag, encoded = "seq", elements
+	case map[string]interface{}:
+		elements := make(map[string]typedElement, len(typed))
+		for key, cur := range typed {
+			element, err := createTypedElement(cur)
+			if err != nil {
+				return typedElement{}, fmt.Errorf("key %q: %v", key, err)
+			}
+			elements[key] = element
+		}
+		tag, encoded = "map", elements
+	default:
+		return typedElement{}, fmt.Errorf("type %T has no JSON type tag", value)
+	}
+
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return typedElement{}, err
+	}
+
+	return typedElement{Type: tag, Value: raw}, nil
+}
+
+func createTypedSeq(raw json.RawMessage) (Seq, error) {
+	var elements []typedElement
+	if err := json.Unmarshal(raw, &elements); err != nil {
+		return nil, err
+	}
+
+	if elements == nil {
+		return nil, nil
+	}
+
+	seq := make(Seq, len(elements))
+
+	for index, element := range elements {
+		value, err := createTypedValue(element)
+		if err != nil {
+			return nil, fmt.Errorf("ugo: element %d: %v", index, err)
+		}
+
+		seq[index] = value
+	}
+
+	return seq, nil
+}
+
+func createTypedValue(element typedElement) (Object, error) {
+	switch element.Type {
+	case "nil":
+		return nil, nil
+	case "bool":
+		var value bool
+		err := json.Unmarshal(element.Value, &value)
+		return value, err
+	case "string":
+		var value string
+		err := json.Unmarshal(element.Value, &value)
+		return value, err
+	case "int", "int8", "int16", "int32", "int64":
+		return createTypedInt(element)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return createTypedUint(element)
+	case "float32":
+		value, err := strconv.ParseFloat(string(element.Value), 32)
+		return float32(value), err
+	case "float64":
+		return strconv.ParseFloat(string(element.Value), 64)
+	case "number":
+		var value json.Number
+		err := createNumberDecoder(element.Value).Decode(&value)
+		return value, err
+	case "seq":
+		return createTypedSeq(element.Value)
+	case "map":
+		var elements map[string]typedElement
+		if err := json.Unmarshal(element.Value, &elements); err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]interface{}, len(elements))
+		for key, cur := range elements {
+			value, err := createTypedValue(cur)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %v", key, err)
+			}
+			values[key] = value
+		}
+
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unknown JSON type tag %q", element.Type)
+}
+
+func createTypedInt(element typedElement) (Object, error) {
+	bits := map[string]int{"int": strconv.IntSize, "int8": 8, "int16": 16, "int32": 32, "int64": 64}[element.Type]
+
+	value, err := strconv.ParseInt(string(element.Value), 10, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	switch element.Type {
+	case "int":
+		return int(value), nil
+	case "int8":
+		return int8(value), nil
+	case "int16":
+		return int16(value), nil
+	case "int32":
+		return int32(value), nil
+	}
+
+	return value, nil
+}
+
+func createTypedUint(element typedElement) (Object, error) {
+	bits := map[string]int{"uint": strconv.IntSize, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64}[element.Type]
+
+	value, err := strconv.ParseUint(string(element.Value), 10, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	switch element.Type {
+	case "uint":
+		return uint(value), nil
+	case "uint8":
+		return uint8(value), nil
+	case "uint16":
+		return uint16(value), nil
+	case "uint32":
+		return uint32(value), nil
+	}
+
+	return value, nil
+}
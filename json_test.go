@@ -0,0 +1,174 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"encoding/json"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#EncodeJSON()", func() {
+		g.It("Should encode Seq as the plain array", func() {
+			data, err := EncodeJSON(Seq{1, "two", 3.5, nil, Seq{true}}, JSONOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(string(data)).Equal(`[1,"two",3.5,null,[true]]`)
+		})
+
+		g.It("Should encode Seq with type tags in typed mode", func() {
+			data, err := EncodeJSON(Seq{1, "two", nil}, JSONOptions{Typed: true})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(string(data)).Equal(`{"$seq":[{"$type":"int","$value":1},{"$type":"string","$value":"two"},{"$type":"nil"}]}`)
+		})
+
+		g.It("Should return an error for the element without type tag", func() {
+			_, err := EncodeJSON(Seq{1, struct{}{}}, JSONOptions{Typed: true})
+
+			g.Assert(err == nil).IsFalse()
+			g.Assert(err.Error()).Equal("ugo: element 1: type struct {} has no JSON type tag")
+		})
+	})
+
+	g.Describe("#DecodeJSON()", func() {
+		g.It("Should preserve element types in typed mode", func() {
+			seq := Seq{
+				1, int8(-2), int16(3), int32(-4), int64(1 << 62),
+				uint(5), uint8(6), uint16(7), uint32(8), uint64(1<<64 - 1),
+				float32(0.1), 0.2, "str", true, nil, json.Number("12.50"),
+				Seq{1, Seq{"nested"}}, map[string]interface{}{"id": 7, "tags": Seq{"a"}},
+			}
+
+			data, err := EncodeJSON(seq, JSONOptions{Typed: true})
+			g.Assert(err == nil).IsTrue()
+
+			decoded, err := DecodeJSON(data, JSONOptions{})
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(seq)
+		})
+
+		g.It("Should decode numbers as float64 by default", func() {
+			decoded, err := DecodeJSON([]byte(`[1, 2.5, {"n": 3}]`), JSONOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(Seq{1.0, 2.5, map[string]interface{}{"n": 3.0}})
+		})
+
+		g.It("Should decode integral numbers as int64", func() {
+			decoded, err := DecodeJSON([]byte(`[1, 2.5, [9007199254740993], 1e400]`), JSONOptions{Numbers: NumberInt64})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded[0]).Equal(int64(1))
+			g.Assert(decoded[1]).Equal(2.5)
+			g.Assert(decoded[2]).Equal([]interface{}{int64(9007199254740993)})
+		})
+
+		g.It("Should decode numbers as json.Number", func() {
+			decoded, err := DecodeJSON([]byte(`[1, 2.50]`), JSONOptions{Numbers: NumberJSON})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(Seq{json.Number("1"), json.Number("2.50")})
+		})
+
+		g.It("Should return error for the data after JSON array", func() {
+			_, err := DecodeJSON([]byte("[1,2] [3]"), JSONOptions{})
+			g.Assert(err.Error()).Equal("ugo: unexpected data after JSON value")
+
+			_, err = DecodeJSON([]byte(`{"$seq":[]} {}`), JSONOptions{})
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should decode null as nil Seq", func() {
+			decoded, err := DecodeJSON([]byte(`null`), JSONOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded == nil).IsTrue()
+		})
+
+		g.It("Should return an error for malformed documents", func() {
+			_, err := DecodeJSON([]byte(`{"items": []}`), JSONOptions{})
+			g.Assert(err == nil).IsFalse()
+
+			_, err = DecodeJSON([]byte(`{"$seq": [{"$type": "complex", "$value": 1}]}`), JSONOptions{})
+			g.Assert(err.Error()).Equal(`ugo: element 0: unknown JSON type tag "complex"`)
+
+			_, err = DecodeJSON([]byte(`{"$seq": [{"$type": "int8", "$value": 300}]}`), JSONOptions{})
+			g.Assert(err == nil).IsFalse()
+
+			_, err = DecodeJSON([]byte(`[1,`), JSONOptions{})
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+
+	g.Describe("#Seq JSON", func() {
+		g.It("Should implement json.Marshaler and json.Unmarshaler", func() {
+			data, err := json.Marshal(map[string]Seq{"items": {1, "a"}})
+			g.Assert(err == nil).IsTrue()
+			g.Assert(string(data)).Equal(`{"items":[1,"a"]}`)
+
+			var decoded map[string]Seq
+			err = json.Unmarshal(data, &decoded)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded["items"]).Equal(Seq{int64(1), "a"})
+		})
+
+		g.It("Should keep integers through the plain round trip", func() {
+			data, err := json.Marshal(Seq{1, 2.5, "a", Seq{-3}, map[string]interface{}{"n": 4}})
+			g.Assert(err == nil).IsTrue()
+
+			var decoded Seq
+			err = json.Unmarshal(data, &decoded)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(decoded).Equal(Seq{int64(1), 2.5, "a", []interface{}{int64(-3)}, map[string]interface{}{"n": int64(4)}})
+		})
+
+		g.It("Should keep element types through TypedSeq", func() {
+			type document struct {
+				Items TypedSeq `json:"items"`
+			}
+
+			data, err := json.Marshal(document{Items: TypedSeq{3, 1, 2}})
+			g.Assert(err == nil).IsTrue()
+
+			var decoded document
+			err = json.Unmarshal(data, &decoded)
+			g.Assert(err == nil).IsTrue()
+
+			sorted := SortBy(Seq(decoded.Items), func(l, r Object) int { return l.(int) - r.(int) })
+			g.Assert(sorted).Equal(Seq{1, 2, 3})
+
+			data, err = json.Marshal(TypedSeq{3, 1, 2})
+			g.Assert(err == nil).IsTrue()
+
+			var plain Seq
+			err = json.Unmarshal(data, &plain)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(plain).Equal(Seq{3, 1, 2})
+		})
+	})
+}
@@ -0,0 +1,775 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is the compiled path into nested Seq, slices, maps and structs, like items[3].tags[0],
+// its segments are separated by dots, fields might be quoted in brackets (a["b.c"]),
+// numeric fields and [n] are indexes of slices, negative indexes count from the end,
+// * and [*] are wildcards, which match every element of slice, every value of map or every exported field of struct,
+// the empty path is the target itself
+type Path struct {
+	source   string
+	segments []pathSegment
+	wildcard bool
+}
+
+// pathSegment is the single step of Path
+type pathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// pathCacheSize is the count of the least recently used paths, which are kept parsed by the package functions
+const pathCacheSize = 256
+
+// pathCache keeps the paths, parsed by the package functions
+var pathCache = newMemoCache(pathCacheSize)
+
+// ParsePath compiles the path
+func ParsePath(source string) (*Path, error) {
+	path := &Path{source: source}
+	pos := 0
+
+	for pos < len(source) {
+		if pos > 0 {
+			switch source[pos] {
+			case '.':
+				pos++
+			case '[':
+			default:
+				return nil, createPathError(source, pos, "expected . or [")
+			}
+		}
+
+		if pos < len(source) && source[pos] == '[' {
+			segment, end, err := parsePathBracket(source, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			path.add(segment)
+			pos = end
+			continue
+		}
+
+		end := pos
+		for end < len(source) && source[end] != '.' && source[end] != '[' {
+			end++
+		}
+
+		if end == pos {
+			return nil, createPathError(source, pos, "expected field name")
+		}
+
+		name := source[pos:end]
+		segment := pathSegment{name: name, wildcard: name == "*"}
+
+		if index, err := strconv.Atoi(name); err == nil {
+			segment.index, segment.isIndex = index, true
+		}
+
+		path.add(segment)
+		pos = end
+	}
+
+	return path, nil
+}
+
+// MustParsePath is like #ParsePath, but it panics if the path is malformed
+func MustParsePath(source string) *Path {
+	path, err := ParsePath(source)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// String returns the source of Path
+func (path *Path) String() string {
+	return path.source
+}
+
+// IsWildcard returns true if Path has wildcards, so it may match many values
+func (path *Path) IsWildcard() bool {
+	return path.wildcard
+}
+
+// Get returns the value by Path or nil if there is no such one,
+// the wildcard Path returns Seq of all of the matched values
+func (path *Path) Get(target Object) Object {
+	if !path.wildcard {
+		value, _ := getPathChild(target, path.segments)
+		return value
+	}
+
+	matches := Seq{}
+	eachPathMatch(target, path.segments, func(value Object) {
+		matches = append(matches, value)
+	})
+
+	return matches
+}
+
+// Has returns true if there is a value by Path, even if it's nil, the wildcard Path needs at least one match
+func (path *Path) Has(target Object) bool {
+	found := false
+	eachPathMatch(target, path.segments, func(Object) { found = true })
+	return found
+}
+
+// Set puts the value by Path and returns the updated target, maps and slices are changed in place,
+// missing map keys are created as map[string]interface{}, the index right after the end of slice appends to it,
+// so the result should be used instead of the target, the wildcard Path sets all of the matched values
+func (path *Path) Set(target, value Object) (Object, error) {
+	return setPathChild(target, path.segments, value, path.source)
+}
+
+// Delete removes the value by Path and returns the updated target and whether anything has been removed,
+// elements of slices are removed with the shift of the rest of them, so the result should be used instead of the target
+func (path *Path) Delete(target Object) (Object, bool) {
+	if len(path.segments) == 0 {
+		return target, false
+	}
+	return deletePathChild(target, path.segments)
+}
+
+// Callback returns the Callback, which takes the value by Path from the current element
+func (path *Path) Callback() Callback {
+	return func(current, _, _ Object) Object {
+		return path.Get(current)
+	}
+}
+
+// GetPath returns the value by path or nil (see #Path.Get), it panics if the path is malformed
+func GetPath(target Object, path string) Object {
+	return createCachedPath(path).Get(target)
+}
+
+// HasPath returns true if there is the value by path (see #Path.Has), it panics if the path is malformed
+func HasPath(target Object, path string) bool {
+	return createCachedPath(path).Has(target)
+}
+
+// SetPath puts the value by path and returns the updated target (see #Path.Set), it panics if the path is malformed
+func SetPath(target Object, path string, value Object) (Object, error) {
+	return createCachedPath(path).Set(target, value)
+}
+
+// DeletePath removes the value by path and returns the updated target (see #Path.Delete),
+// it panics if the path is malformed
+func DeletePath(target Object, path string) (Object, bool) {
+	return createCachedPath(path).Delete(target)
+}
+
+// PathCallback returns the Callback, which takes the value by path from the current element,
+// to be used with #Map, #GroupBy or #CompareBy, it panics if the path is malformed
+func PathCallback(path string) Callback {
+	return createCachedPath(path).Callback()
+}
+
+/* private methods */
+func (path *Path) add(segment pathSegment) {
+	path.segments = append(path.segments, segment)
+	path.wildcard = path.wildcard || segment.wildcard
+}
+
+func createCachedPath(source string) *Path {
+	if cached, ok := pathCache.get(source); ok {
+		return cached.(*Path)
+	}
+
+	path := MustParsePath(source)
+	pathCache.put(source, path)
+
+	return path
+}
+
+func createPathError(source string, pos int, message string) error {
+	return fmt.Errorf("ugo: path %q at column %d: %s", source, pos+1, message)
+}
+
+// parsePathBracket parses [n], [*] or ["name"] at pos and returns the position after it
+func parsePathBracket(source string, pos int) (pathSegment, int, error) {
+	end := strings.IndexByte(source[pos:], ']')
+	if end < 0 {
+		return pathSegment{}, 0, createPathError(source, pos, "unterminated [")
+	}
+
+	inner := source[pos+1 : pos+end]
+
+	if strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'") {
+		name, rest, err := parsePathQuoted(source, pos+1)
+		if err != nil {
+			return pathSegment{}, 0, err
+		}
+		if rest >= len(source) || source[rest] != ']' {
+			return pathSegment{}, 0, createPathError(source, rest, "expected ]")
+		}
+		return pathSegment{name: name}, rest + 1, nil
+	}
+
+	if inner == "*" {
+		return pathSegment{wildcard: true}, pos + end + 1, nil
+	}
+
+	index, err := strconv.Atoi(strings.TrimSpace(inner))
+	if err != nil {
+		return pathSegment{}, 0, createPathError(source, pos+1, "expected index, * or quoted name")
+	}
+
+	return pathSegment{name: inner, index: index, isIndex: true}, pos + end + 1, nil
+}
+
+func parsePathQuoted(source string, pos int) (string, int, error) {
+	quote := source[pos]
+	var name strings.Builder
+
+	for end := pos + 1; end < len(source); end++ {
+		switch source[end] {
+		case quote:
+			return name.String(), end + 1, nil
+		case '\\':
+			if end+1 < len(source) {
+				end++
+			}
+		}
+		name.WriteByte(source[end])
+	}
+
+	return "", 0, createPathError(source, pos, "unterminated string")
+}
+
+// getPathChild walks through the path without wildcards, returns false if some step is missing
+func getPathChild(target Object, segments []pathSegment) (Object, bool) {
+	for _, segment := range segments {
+		child, ok := getPathStep(target, segment)
+		if !ok {
+			return nil, false
+		}
+		target = child
+	}
+	return target, true
+}
+
+// eachPathMatch calls cb for every value, matched by the path
+func eachPathMatch(target Object, segments []pathSegment, cb func(value Object)) {
+	for len(segments) > 0 && !segments[0].wildcard {
+		child, ok := getPathStep(target, segments[0])
+		if !ok {
+			return
+		}
+		target, segments = child, segments[1:]
+	}
+
+	if len(segments) == 0 {
+		cb(target)
+		return
+	}
+
+	for _, child := range getPathChildren(target) {
+		eachPathMatch(child, segments[1:], cb)
+	}
+}
+
+// getPathStep returns the child of target by the segment without wildcard
+func getPathStep(target Object, segment pathSegment) (Object, bool) {
+	switch typed := target.(type) {
+	case nil:
+		return nil, false
+	case map[string]interface{}:
+		value, ok := typed[segment.name]
+		return value, ok
+	case map[string]Object:
+		value, ok := typed[segment.name]
+		return value, ok
+	case Seq:
+		return getPathElement(typed, segment)
+	case []interface{}:
+		return getPathElement(typed, segment)
+	}
+
+	reflected := createPathValue(target)
+
+	switch reflected.Kind() {
+	case reflect.Map:
+		key, ok := createPathKey(reflected, segment)
+		if !ok {
+			return nil, false
+		}
+
+		value := reflected.MapIndex(key)
+		if !value.IsValid() {
+			return nil, false
+		}
+		return value.Interface(), true
+	case reflect.Slice, reflect.Array:
+		position, ok := fixPathIndex(segment, reflected.Len())
+		if !ok {
+			return nil, false
+		}
+		return reflected.Index(position).Interface(), true
+	case reflect.Struct:
+		field, ok := reflected.Type().FieldByName(segment.name)
+		if !ok || field.PkgPath != "" {
+			return nil, false
+		}
+		return reflected.FieldByIndex(field.Index).Interface(), true
+	}
+
+	return nil, false
+}
+
+func getPathElement(seq Seq, segment pathSegment) (Object, bool) {
+	position, ok := fixPathIndex(segment, len(seq))
+	if !ok {
+		return nil, false
+	}
+	return seq[position], true
+}
+
+// getPathChildren returns all of the children of target, values of maps are ordered by their keys
+func getPathChildren(target Object) Seq {
+	reflected := createPathValue(target)
+	children := Seq{}
+
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < reflected.Len(); index++ {
+			children = append(children, reflected.Index(index).Interface())
+		}
+	case reflect.Map:
+		keys := reflected.MapKeys()
+		sort.SliceStable(keys, func(i, j int) bool { return Compare(keys[i].Interface(), keys[j].Interface()) < 0 })
+
+		for _, key := range keys {
+			children = append(children, reflected.MapIndex(key).Interface())
+		}
+	case reflect.Struct:
+		for index := 0; index < reflected.NumField(); index++ {
+			if reflected.Type().Field(index).PkgPath == "" {
+				children = append(children, reflected.Field(index).Interface())
+			}
+		}
+	}
+
+	return children
+}
+
+// createPathValue returns the reflected target without pointers and interfaces
+func createPathValue(target Object) reflect.Value {
+	reflected := reflect.ValueOf(target)
+	for reflected.Kind() == reflect.Ptr || reflected.Kind() == reflect.Interface {
+		if reflected.IsNil() {
+			return reflect.Value{}
+		}
+		reflected = reflected.Elem()
+	}
+	return reflected
+}
+
+// createPathKey converts the segment into the key of reflected map, names are used for string keys, indexes for integer ones
+func createPathKey(reflected reflect.Value, segment pathSegment) (reflect.Value, bool) {
+	keyType := reflected.Type().Key()
+
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(segment.name).Convert(keyType), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if segment.isIndex {
+			return reflect.ValueOf(segment.index).Convert(keyType), true
+		}
+	case reflect.Interface:
+		if segment.isIndex {
+			return reflect.ValueOf(segment.index), true
+		}
+		return reflect.ValueOf(segment.name), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// fixPathIndex returns the position in slice of given length by the index segment, negative ones count from the end
+func fixPathIndex(segment pathSegment, length int) (int, bool) {
+	if !segment.isIndex {
+		return 0, false
+	}
+
+	position := segment.index
+	if position < 0 {
+		position += length
+	}
+
+	return position, position >= 0 && position < length
+}
+
+func setPathChild(target Object, segments []pathSegment, value Object, source string) (Object, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment.wildcard {
+		var failure error
+
+		eachPathSlot(target, source, func(child Object, replace func(Object) error) {
+			if failure != nil {
+				return
+			}
+
+			updated, err := setPathChild(child, rest, value, source)
+			if err == nil {
+				err = replace(updated)
+			}
+			failure = err
+		})
+
+		return target, failure
+	}
+
+	switch typed := target.(type) {
+	case nil:
+		if segment.isIndex {
+			if segment.index != 0 {
+				return nil, fmt.Errorf("ugo: path %q: index %d is out of range of empty slice", source, segment.index)
+			}
+			child, err := setPathChild(nil, rest, value, source)
+			return []interface{}{child}, err
+		}
+
+		child, err := setPathChild(nil, rest, value, source)
+		return map[string]interface{}{segment.name: child}, err
+	case map[string]interface{}:
+		child, err := setPathChild(typed[segment.name], rest, value, source)
+		if err == nil {
+			typed[segment.name] = child
+		}
+		return typed, err
+	case map[string]Object:
+		child, err := setPathChild(typed[segment.name], rest, value, source)
+		if err == nil {
+			typed[segment.name] = child
+		}
+		return typed, err
+	case Seq:
+		seq, err := setPathElement(typed, segment, rest, value, source)
+		return seq, err
+	case []interface{}:
+		seq, err := setPathElement(typed, segment, rest, value, source)
+		return []interface{}(seq), err
+	}
+
+	reflected := createPathValue(target)
+
+	switch reflected.Kind() {
+	case reflect.Map:
+		key, ok := createPathKey(reflected, segment)
+		if !ok {
+			break
+		}
+
+		var current Object
+		if found := reflected.MapIndex(key); found.IsValid() {
+			current = found.Interface()
+		}
+
+		child, err := setPathChild(current, rest, value, source)
+		if err != nil {
+			return target, err
+		}
+
+		if err = setPathReflected(reflected, key, child, source); err != nil {
+			return target, err
+		}
+
+		return target, nil
+	case reflect.Slice, reflect.Array, reflect.Struct:
+		slot, ok := createPathSlot(reflected, segment)
+		if !ok || !slot.CanSet() {
+			break
+		}
+
+		child, replace := createPathChild(slot, source)
+		updated, err := setPathChild(child, rest, value, source)
+		if err != nil {
+			return target, err
+		}
+
+		return target, replace(updated)
+	}
+
+	return target, fmt.Errorf("ugo: path %q: can't set %s in %T", source, segment.describe(), target)
+}
+
+func setPathElement(seq Seq, segment pathSegment, rest []pathSegment, value Object, source string) (Seq, error) {
+	if !segment.isIndex {
+		return seq, fmt.Errorf("ugo: path %q: can't set %s in slice", source, segment.describe())
+	}
+
+	if segment.index == len(seq) {
+		child, err := setPathChild(nil, rest, value, source)
+		if err != nil {
+			return seq, err
+		}
+		return append(seq, child), nil
+	}
+
+	position, ok := fixPathIndex(segment, len(seq))
+	if !ok {
+		return seq, fmt.Errorf("ugo: path %q: index %d is out of range of %d elements", source, segment.index, len(seq))
+	}
+
+	child, err := setPathChild(seq[position], rest, value, source)
+	if err == nil {
+		seq[position] = child
+	}
+
+	return seq, err
+}
+
+// createPathSlot returns the settable element of slice, array or the exported field of struct
+func createPathSlot(reflected reflect.Value, segment pathSegment) (reflect.Value, bool) {
+	if reflected.Kind() == reflect.Struct {
+		field, ok := reflected.Type().FieldByName(segment.name)
+		if !ok || field.PkgPath != "" {
+			return reflect.Value{}, false
+		}
+		return reflected.FieldByIndex(field.Index), true
+	}
+
+	position, ok := fixPathIndex(segment, reflected.Len())
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	return reflected.Index(position), true
+}
+
+// setPathReflected puts the value into map by key or into the settable slot, if key is invalid
+func setPathReflected(target, key reflect.Value, value Object, source string) error {
+	valueType := target.Type()
+	if key.IsValid() {
+		valueType = target.Type().Elem()
+	}
+
+	converted := reflect.Zero(valueType)
+	if value != nil {
+		converted = reflect.ValueOf(value)
+		if !converted.Type().AssignableTo(valueType) {
+			return fmt.Errorf("ugo: path %q: can't use %T as %s", source, value, valueType)
+		}
+	}
+
+	if key.IsValid() {
+		target.SetMapIndex(key, converted)
+	} else {
+		target.Set(converted)
+	}
+
+	return nil
+}
+
+// eachPathSlot calls cb for every child of target with the function, which replaces it
+func eachPathSlot(target Object, source string, cb func(child Object, replace func(Object) error)) {
+	switch typed := target.(type) {
+	case map[string]interface{}:
+		for _, key := range createSortedKeys(typed) {
+			cb(typed[key], func(value Object) error { typed[key] = value; return nil })
+		}
+		return
+	case map[string]Object:
+		for key, child := range typed {
+			key := key
+			cb(child, func(value Object) error { typed[key] = value; return nil })
+		}
+		return
+	}
+
+	reflected := createPathValue(target)
+
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < reflected.Len(); index++ {
+			cb(createPathChild(reflected.Index(index), source))
+		}
+	case reflect.Map:
+		for _, key := range reflected.MapKeys() {
+			key := key
+			cb(reflected.MapIndex(key).Interface(), func(value Object) error {
+				return setPathReflected(reflected, key, value, source)
+			})
+		}
+	case reflect.Struct:
+		for index := 0; index < reflected.NumField(); index++ {
+			if reflected.Type().Field(index).PkgPath == "" {
+				cb(createPathChild(reflected.Field(index), source))
+			}
+		}
+	}
+}
+
+// createPathChild returns the value of slot and the function, which replaces it,
+// addressable structs are returned by pointer, so their fields are changed in place
+func createPathChild(slot reflect.Value, source string) (Object, func(Object) error) {
+	if slot.Kind() == reflect.Struct && slot.CanAddr() {
+		pointer := slot.Addr().Interface()
+
+		return pointer, func(value Object) error {
+			if value == pointer {
+				return nil
+			}
+			return setPathSlot(slot, value, source)
+		}
+	}
+
+	return slot.Interface(), func(value Object) error {
+		return setPathSlot(slot, value, source)
+	}
+}
+
+func setPathSlot(slot reflect.Value, value Object, source string) error {
+	if !slot.CanSet() {
+		return fmt.Errorf("ugo: path %q: can't set %s, it's not addressable", source, slot.Type())
+	}
+	return setPathReflected(slot, reflect.Value{}, value, source)
+}
+
+func createSortedKeys(target map[string]interface{}) []string {
+	keys := make([]string, 0, len(target))
+	for key := range target {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func deletePathChild(target Object, segments []pathSegment) (Object, bool) {
+	segment, rest := segments[0], segments[1:]
+
+	if segment.wildcard {
+		if len(rest) == 0 {
+			return deletePathAll(target)
+		}
+
+		deleted := false
+		eachPathSlot(target, "", func(child Object, replace func(Object) error) {
+			updated, ok := deletePathChild(child, rest)
+			if ok && replace(updated) == nil {
+				deleted = true
+			}
+		})
+		return target, deleted
+	}
+
+	if len(rest) > 0 {
+		child, ok := getPathStep(target, segment)
+		if !ok {
+			return target, false
+		}
+
+		updated, deleted := deletePathChild(child, rest)
+		if !deleted {
+			return target, false
+		}
+
+		result, err := setPathChild(target, segments[:1], updated, "")
+		return result, err == nil
+	}
+
+	switch typed := target.(type) {
+	case map[string]interface{}:
+		_, ok := typed[segment.name]
+		delete(typed, segment.name)
+		return typed, ok
+	case map[string]Object:
+		_, ok := typed[segment.name]
+		delete(typed, segment.name)
+		return typed, ok
+	case Seq:
+		return deletePathElement(typed, segment)
+	case []interface{}:
+		seq, ok := deletePathElement(typed, segment)
+		return []interface{}(seq.(Seq)), ok
+	}
+
+	reflected := createPathValue(target)
+	if reflected.Kind() == reflect.Map {
+		key, ok := createPathKey(reflected, segment)
+		if ok && reflected.MapIndex(key).IsValid() {
+			reflected.SetMapIndex(key, reflect.Value{})
+			return target, true
+		}
+	}
+
+	return target, false
+}
+
+func deletePathElement(seq Seq, segment pathSegment) (Object, bool) {
+	position, ok := fixPathIndex(segment, len(seq))
+	if !ok {
+		return seq, false
+	}
+	return append(seq[:position], seq[position+1:]...), true
+}
+
+// deletePathAll removes all of the children of map or slice
+func deletePathAll(target Object) (Object, bool) {
+	switch typed := target.(type) {
+	case Seq:
+		return typed[:0], len(typed) > 0
+	case []interface{}:
+		return typed[:0], len(typed) > 0
+	}
+
+	reflected := createPathValue(target)
+	if reflected.Kind() != reflect.Map {
+		return target, false
+	}
+
+	keys := reflected.MapKeys()
+	for _, key := range keys {
+		reflected.SetMapIndex(key, reflect.Value{})
+	}
+
+	return target, len(keys) > 0
+}
+
+func (segment pathSegment) describe() string {
+	switch {
+	case segment.wildcard:
+		return "*"
+	case segment.isIndex:
+		return fmt.Sprintf("index %d", segment.index)
+	}
+	return fmt.Sprintf("field %q", segment.name)
+}
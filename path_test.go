@@ -0,0 +1,251 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+type pathOrder struct {
+	ID    int
+	Items []pathItem
+	Meta  map[string]int
+	note  string
+}
+
+type pathItem struct {
+	Name string
+	Qty  int
+}
+
+func TestPaths(t *testing.T) {
+	g := Goblin(t)
+
+	decode := func(data string) Object {
+		var value interface{}
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			panic(err)
+		}
+		return value
+	}
+
+	document := `{"items": [
+		{"id": 1, "tags": ["a", "b"]},
+		{"id": 2, "tags": []},
+		{"id": 3, "tags": ["c"], "dims.cm": {"w": 10}}
+	], "owner": {"name": "ann", "nick": null}}`
+
+	g.Describe("#ParsePath()", func() {
+		g.It("Should parse all kinds of segments", func() {
+			path, err := ParsePath(`items[3].tags[-1]["dots.in.name"].*['q\'s'][*]`)
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(path.IsWildcard()).IsTrue()
+			g.Assert(MustParsePath("a.b").IsWildcard()).IsFalse()
+			g.Assert(MustParsePath("").String()).Equal("")
+		})
+
+		g.It("Should return errors with positions", func() {
+			_, err := ParsePath("items[")
+			g.Assert(err.Error()).Equal(`ugo: path "items[" at column 6: unterminated [`)
+
+			_, err = ParsePath("items[x]")
+			g.Assert(err.Error()).Equal(`ugo: path "items[x]" at column 7: expected index, * or quoted name`)
+
+			_, err = ParsePath("a..b")
+			g.Assert(err.Error()).Equal(`ugo: path "a..b" at column 3: expected field name`)
+
+			_, err = ParsePath(`a["b]`)
+			g.Assert(err.Error()).Equal(`ugo: path "a[\"b]" at column 3: unterminated string`)
+
+			_, err = ParsePath("a[0]b")
+			g.Assert(err.Error()).Equal(`ugo: path "a[0]b" at column 5: expected . or [`)
+		})
+	})
+
+	g.Describe("#GetPath()", func() {
+		g.It("Should get nested values of decoded JSON", func() {
+			target := decode(document)
+
+			g.Assert(GetPath(target, "items[0].tags[1]")).Equal("b")
+			g.Assert(GetPath(target, "items.2.id")).Equal(3.0)
+			g.Assert(GetPath(target, "items[-1]['dims.cm'].w")).Equal(10.0)
+			g.Assert(GetPath(target, "items[5].id") == nil).IsTrue()
+			g.Assert(GetPath(target, "owner.name.first") == nil).IsTrue()
+			g.Assert(GetPath(target, "")).Equal(target)
+		})
+
+		g.It("Should get values by paths, which have been evicted from the cache", func() {
+			target := map[string]Object{}
+			for index := 0; index < 1000; index++ {
+				target[fmt.Sprintf("key%d", index)] = index
+			}
+
+			for round := 0; round < 2; round++ {
+				for index := 0; index < 1000; index++ {
+					g.Assert(GetPath(target, fmt.Sprintf("key%d", index))).Equal(index)
+				}
+			}
+		})
+
+		g.It("Should return Seq for wildcard paths", func() {
+			target := decode(document)
+
+			g.Assert(GetPath(target, "items[*].id")).Equal(Seq{1.0, 2.0, 3.0})
+			g.Assert(GetPath(target, "items[*].tags[*]")).Equal(Seq{"a", "b", "c"})
+			g.Assert(GetPath(target, "owner.*")).Equal(Seq{"ann", nil})
+			g.Assert(GetPath(target, "missing[*]")).Equal(Seq{})
+		})
+
+		g.It("Should get fields of structs and typed maps", func() {
+			order := &pathOrder{ID: 7, Items: []pathItem{{"pen", 2}, {"ink", 5}}, Meta: map[string]int{"rush": 1}}
+
+			g.Assert(GetPath(order, "Items[1].Qty")).Equal(5)
+			g.Assert(GetPath(order, "Items[*].Name")).Equal(Seq{"pen", "ink"})
+			g.Assert(GetPath(order, "Meta.rush")).Equal(1)
+			g.Assert(GetPath(order, "note") == nil).IsTrue()
+			g.Assert(GetPath(Seq{map[int]string{2: "two"}}, "[0][2]")).Equal("two")
+		})
+
+		g.It("Should panic for malformed path", func() {
+			defer func() {
+				g.Assert(recover() == nil).IsFalse()
+			}()
+
+			GetPath(nil, "a[")
+		})
+	})
+
+	g.Describe("#HasPath()", func() {
+		g.It("Should check whether the value exists", func() {
+			target := decode(document)
+
+			g.Assert(HasPath(target, "owner.nick")).IsTrue()
+			g.Assert(HasPath(target, "owner.age")).IsFalse()
+			g.Assert(HasPath(target, "items[*].tags[1]")).IsTrue()
+			g.Assert(HasPath(target, "items[*].tags[2]")).IsFalse()
+			g.Assert(HasPath(nil, "a")).IsFalse()
+		})
+	})
+
+	g.Describe("#SetPath()", func() {
+		g.It("Should set values in place and create missing maps", func() {
+			target := decode(document)
+
+			result, err := SetPath(target, "items[1].tags[0]", "new")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(GetPath(result, "items[1].tags")).Equal([]interface{}{"new"})
+
+			result, err = SetPath(result, "owner.address.city", "Oslo")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(GetPath(target, "owner.address")).Equal(map[string]interface{}{"city": "Oslo"})
+
+			result, err = SetPath(result, "items[*].seen", true)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(GetPath(result, "items[*].seen")).Equal(Seq{true, true, true})
+
+			created, err := SetPath(nil, "a[0].b", 1)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(created).Equal(map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1}}})
+		})
+
+		g.It("Should set fields of structs by pointer", func() {
+			order := &pathOrder{Items: []pathItem{{"pen", 2}}, Meta: map[string]int{}}
+
+			_, err := SetPath(order, "Items[0].Qty", 3)
+			g.Assert(err == nil).IsTrue()
+			_, err = SetPath(order, "Meta.rush", 1)
+			g.Assert(err == nil).IsTrue()
+			_, err = SetPath(order, "Items[*].Name", "pencil")
+			g.Assert(err == nil).IsTrue()
+
+			g.Assert(order.Items).Equal([]pathItem{{"pencil", 3}})
+			g.Assert(order.Meta).Equal(map[string]int{"rush": 1})
+
+			_, err = SetPath(order, "ID", "seven")
+			g.Assert(err.Error()).Equal(`ugo: path "ID": can't use string as int`)
+
+			_, err = SetPath(*order, "ID", 1)
+			g.Assert(err.Error()).Equal(`ugo: path "ID": can't set field "ID" in ugo_test.pathOrder`)
+		})
+
+		g.It("Should append to slices and report indexes out of range", func() {
+			seq := Seq{1, 2}
+
+			result, err := SetPath(seq, "[2]", 3)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(result).Equal(Seq{1, 2, 3})
+
+			_, err = SetPath(seq, "[5]", 3)
+			g.Assert(err.Error()).Equal(`ugo: path "[5]": index 5 is out of range of 2 elements`)
+
+			_, err = SetPath(seq, "name", 3)
+			g.Assert(err.Error()).Equal(`ugo: path "name": can't set field "name" in slice`)
+		})
+	})
+
+	g.Describe("#DeletePath()", func() {
+		g.It("Should delete keys and elements", func() {
+			target := decode(document)
+
+			result, ok := DeletePath(target, "items[0].tags[0]")
+			g.Assert(ok).IsTrue()
+			g.Assert(GetPath(result, "items[0].tags")).Equal([]interface{}{"b"})
+
+			result, ok = DeletePath(result, "items[*].tags")
+			g.Assert(ok).IsTrue()
+			g.Assert(HasPath(result, "items[*].tags")).IsFalse()
+
+			result, ok = DeletePath(result, "items[1]")
+			g.Assert(ok).IsTrue()
+			g.Assert(GetPath(result, "items[*].id")).Equal(Seq{1.0, 3.0})
+
+			_, ok = DeletePath(result, "owner.age")
+			g.Assert(ok).IsFalse()
+
+			result, ok = DeletePath(result, "owner.*")
+			g.Assert(ok).IsTrue()
+			g.Assert(GetPath(result, "owner")).Equal(map[string]interface{}{})
+		})
+	})
+
+	g.Describe("#PathCallback()", func() {
+		g.It("Should be used with Map, GroupBy and CompareBy", func() {
+			items := GetPath(decode(document), "items").([]interface{})
+			seq := Seq(items)
+
+			g.Assert(Map(seq, PathCallback("tags[0]"))).Equal(Seq{"a", nil, "c"})
+
+			groups := GroupBy(seq, func(cur, key, src Object) Object { return len(PathCallback("tags")(cur, key, src).([]interface{})) })
+			g.Assert(len(groups)).Equal(3)
+			g.Assert(groups[2]).Equal(Seq{items[0]})
+
+			sorted := SortedCopy(seq, NegateComparator(CompareBy(PathCallback("id"))))
+			g.Assert(Map(sorted, PathCallback("id"))).Equal(Seq{3.0, 2.0, 1.0})
+		})
+	})
+}
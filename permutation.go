@@ -0,0 +1,89 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// NextPermutation rearranges slice into the next permutation in lexicographic order by Comparator,
+// returns false if slice has been the last permutation, rearranging it into the first one, e. g. sorted
+func NextPermutation(seq Seq, cb Comparator) bool {
+	if cb == nil || len(seq) < 2 {
+		return false
+	}
+
+	pivot := len(seq) - 2
+	for pivot >= 0 && cb(seq[pivot], seq[pivot+1]) >= 0 {
+		pivot--
+	}
+
+	if pivot < 0 {
+		createReverse(seq, len(seq))
+		return false
+	}
+
+	successor := len(seq) - 1
+	for cb(seq[successor], seq[pivot]) <= 0 {
+		successor--
+	}
+
+	seq[pivot], seq[successor] = seq[successor], seq[pivot]
+	createReverse(seq[pivot+1:], len(seq)-pivot-1)
+
+	return true
+}
+
+// Permutations returns Iterator over all distinct permutations of slice in lexicographic order by Comparator,
+// if Comparator is not passed, it goes through all permutations of positions,
+// every permutation is a new Seq, given slice stays untouched
+func Permutations(seq Seq, cb Comparator) Iterator {
+	return func(yield func(current Object) bool) {
+		if cb != nil {
+			current := SortedCopy(seq, cb)
+			for ok := true; ok; ok = NextPermutation(current, cb) {
+				if !yield(createCopy(current)) {
+					return
+				}
+			}
+			return
+		}
+
+		positions := NewSeq(len(seq))
+		for i := range positions {
+			positions[i] = i
+		}
+
+		for ok := true; ok; ok = NextPermutation(positions, comparePositions) {
+			current := NewSeq(len(seq))
+			for i, position := range positions {
+				current[i] = seq[position.(int)]
+			}
+			if !yield(current) {
+				return
+			}
+		}
+	}
+}
+
+/* private methods */
+// comparePositions is the Comparator for int positions
+func comparePositions(left, right Object) int {
+	return left.(int) - right.(int)
+}
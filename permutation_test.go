@@ -0,0 +1,159 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"fmt"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestPermutations(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+
+	collect := func(it Iterator) Seq {
+		result := NewSeq(0)
+		it(func(cur Object) bool {
+			result = append(result, cur)
+			return true
+		})
+		return result
+	}
+
+	g.Describe("#NextPermutation()", func() {
+		g.It("Should rearrange Seq into the next permutation", func() {
+			inSeq := Seq{1, 2, 3}
+
+			g.Assert(NextPermutation(inSeq, intComparator)).IsTrue()
+			g.Assert(inSeq).Equal(Seq{1, 3, 2})
+			g.Assert(NextPermutation(inSeq, intComparator)).IsTrue()
+			g.Assert(inSeq).Equal(Seq{2, 1, 3})
+		})
+
+		g.It("Should return false and sort Seq after the last permutation", func() {
+			inSeq := Seq{3, 2, 1}
+
+			g.Assert(NextPermutation(inSeq, intComparator)).IsFalse()
+			g.Assert(inSeq).Equal(Seq{1, 2, 3})
+			g.Assert(NextPermutation(Seq{1}, intComparator)).IsFalse()
+			g.Assert(NextPermutation(nil, intComparator)).IsFalse()
+			g.Assert(NextPermutation(Seq{1, 2}, nil)).IsFalse()
+		})
+
+		g.It("Should skip equal permutations", func() {
+			inSeq := Seq{1, 1, 2}
+
+			g.Assert(NextPermutation(inSeq, intComparator)).IsTrue()
+			g.Assert(inSeq).Equal(Seq{1, 2, 1})
+			g.Assert(NextPermutation(inSeq, intComparator)).IsTrue()
+			g.Assert(inSeq).Equal(Seq{2, 1, 1})
+			g.Assert(NextPermutation(inSeq, intComparator)).IsFalse()
+		})
+	})
+
+	g.Describe("#Permutations()", func() {
+		g.It("Should enumerate distinct permutations in lexicographic order", func() {
+			inSeq := Seq{2, 1, 2}
+
+			g.Assert(collect(Permutations(inSeq, intComparator))).Equal(Seq{Seq{1, 2, 2}, Seq{2, 1, 2}, Seq{2, 2, 1}})
+			g.Assert(len(collect(Permutations(Seq{1, 2, 3, 4, 5}, intComparator)))).Equal(120)
+			g.Assert(inSeq).Equal(Seq{2, 1, 2})
+		})
+
+		g.It("Should enumerate permutations of positions without Comparator", func() {
+			g.Assert(collect(Permutations(Seq{"b", "a"}, nil))).Equal(Seq{Seq{"b", "a"}, Seq{"a", "b"}})
+			g.Assert(len(collect(Permutations(Seq{1, 1, 1}, nil)))).Equal(6)
+			g.Assert(collect(Permutations(nil, nil))).Equal(Seq{Seq{}})
+		})
+
+		g.It("Should stop when yield returns false", func() {
+			count := 0
+			Permutations(Seq{1, 2, 3, 4}, intComparator)(func(_ Object) bool {
+				count++
+				return count < 5
+			})
+
+			g.Assert(count).Equal(5)
+		})
+	})
+
+	g.Describe("#Shuffle() uniformity", func() {
+		// chiSquare returns the chi-square statistic of counts against the uniform distribution
+		chiSquare := func(counts map[string]int, categories, trials int) float64 {
+			expected := float64(trials) / float64(categories)
+			stat := 0.0
+			for _, count := range counts {
+				diff := float64(count) - expected
+				stat += diff * diff / expected
+			}
+			return stat + float64(categories-len(counts))*expected
+		}
+
+		g.It("Should produce every permutation equally likely", func() {
+			inSeq := Seq{0, 1, 2, 3}
+			rng := NewRand(2016)
+			counts := make(map[string]int)
+			trials := 48000
+
+			for i := 0; i < trials; i++ {
+				counts[fmt.Sprint(ShuffledCopyWith(inSeq, rng))]++
+			}
+
+			Permutations(inSeq, intComparator)(func(cur Object) bool {
+				g.Assert(counts[fmt.Sprint(cur)] > 0).IsTrue()
+				return true
+			})
+
+			// 23 degrees of freedom, critical value for p = 0.001
+			g.Assert(len(counts)).Equal(24)
+			g.Assert(chiSquare(counts, 24, trials) < 49.728).IsTrue()
+		})
+
+		g.It("Should put every element to every position equally likely", func() {
+			rng := NewRand(1984)
+			trials := 20000
+
+			for position := 0; position < 5; position++ {
+				counts := make(map[string]int)
+				for i := 0; i < trials; i++ {
+					counts[fmt.Sprint(ShuffleWith(Seq{0, 1, 2, 3, 4}, rng)[position])]++
+				}
+
+				// 4 degrees of freedom, critical value for p = 0.001
+				g.Assert(chiSquare(counts, 5, trials) < 18.467).IsTrue()
+			}
+		})
+
+		g.It("Should shuffle with the default Rand", func() {
+			counts := make(map[string]int)
+			for i := 0; i < 6000; i++ {
+				counts[fmt.Sprint(ShuffledCopy(Seq{0, 1, 2}))]++
+			}
+
+			g.Assert(len(counts)).Equal(6)
+		})
+	})
+}
@@ -0,0 +1,691 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QueryBuilder is the SQL-like query over Seq of structs or maps, which is compiled down to
+// #Filter, #GroupBy, #Map and #SortBy, all of its clauses are expressions (see #Expr),
+// where fields of the element can be used without it, e.g. "Age > 18" is the same as "it.Age > 18"
+type QueryBuilder struct {
+	seq     Seq
+	where   []*Expr
+	groupBy []*Expr
+	selects []querySelect
+	orderBy []queryOrder
+	offset  int
+	limit   int
+	err     error
+}
+
+// querySelect is the column of the result, aggregate is set for the aggregating functions
+type querySelect struct {
+	name      string
+	expr      *Expr
+	aggregate string
+}
+
+// queryOrder is the sorting key of the result
+type queryOrder struct {
+	expr *Expr
+	desc bool
+}
+
+var (
+	queryAlias     = regexp.MustCompile(`(?is)^(.*?)\s+as\s+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+	queryAggregate = regexp.MustCompile(`(?is)^\s*(count|sum|avg|min|max)\s*\((.*)\)\s*$`)
+	queryDirection = regexp.MustCompile(`(?is)^(.*?)\s+(asc|desc)\s*$`)
+	queryPath      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// Query returns the new QueryBuilder over given Seq, which returns its elements as they are
+func Query(seq Seq) *QueryBuilder {
+	return &QueryBuilder{seq: seq, limit: -1}
+}
+
+// Where leaves only elements, for which the condition is true, conditions of several calls are joined by &&,
+// nil results are treated as false
+func (query *QueryBuilder) Where(condition string) *QueryBuilder {
+	if expr := query.compile(condition); expr != nil {
+		query.where = append(query.where, expr)
+	}
+	return query
+}
+
+// GroupBy groups elements by values of the keys, so every group becomes a single row of the result,
+// columns of grouped query should be either aggregating functions or keys of the group
+func (query *QueryBuilder) GroupBy(keys ...string) *QueryBuilder {
+	for _, key := range keys {
+		if expr := query.compile(key); expr != nil {
+			query.groupBy = append(query.groupBy, expr)
+		}
+	}
+	return query
+}
+
+// Select makes rows of the result map[string]Object with given columns, each column is an expression with
+// optional alias like "Age * 12 as months", path columns are named by their last field, the rest of them by source,
+// the whole column might be one of aggregating functions: count(), count(expr), sum(expr), avg(expr), min(expr)
+// and max(expr), they make the query grouped, so without #GroupBy all elements are aggregated into one row
+func (query *QueryBuilder) Select(columns ...string) *QueryBuilder {
+	for _, column := range columns {
+		selected := querySelect{name: createColumnName(column)}
+
+		if match := queryAlias.FindStringSubmatch(column); match != nil {
+			column, selected.name = match[1], match[2]
+		}
+
+		if match := queryAggregate.FindStringSubmatch(column); match != nil && !strings.Contains(match[2], ",") {
+			selected.aggregate = strings.ToLower(match[1])
+			column = strings.TrimSpace(match[2])
+
+			if column == "" || column == "*" {
+				if selected.aggregate != "count" {
+					query.fail(fmt.Errorf("ugo: query: %s needs an argument", selected.aggregate))
+					continue
+				}
+				column = "true"
+			}
+		}
+
+		if selected.expr = query.compile(column); selected.expr != nil {
+			query.selects = append(query.selects, selected)
+		}
+	}
+	return query
+}
+
+// OrderBy sorts rows of the result stably by given keys, each of them might be followed by asc or desc,
+// keys are evaluated on the rows of the result, so they refer to the columns of #Select
+func (query *QueryBuilder) OrderBy(keys ...string) *QueryBuilder {
+	for _, key := range keys {
+		order := queryOrder{}
+
+		if match := queryDirection.FindStringSubmatch(key); match != nil {
+			key, order.desc = match[1], strings.EqualFold(match[2], "desc")
+		}
+
+		if order.expr = query.compile(key); order.expr != nil {
+			query.orderBy = append(query.orderBy, order)
+		}
+	}
+	return query
+}
+
+// Limit leaves at most n rows of the result
+func (query *QueryBuilder) Limit(n int) *QueryBuilder {
+	if n < 0 {
+		query.fail(fmt.Errorf("ugo: query: negative limit %d", n))
+	}
+	query.limit = n
+	return query
+}
+
+// Offset skips the first n rows of the result
+func (query *QueryBuilder) Offset(n int) *QueryBuilder {
+	if n < 0 {
+		query.fail(fmt.Errorf("ugo: query: negative offset %d", n))
+	}
+	query.offset = n
+	return query
+}
+
+// Err returns the first error of building the query
+func (query *QueryBuilder) Err() error {
+	return query.err
+}
+
+// Explain describes the steps of the query, one per line, in order they're done
+func (query *QueryBuilder) Explain() string {
+	var plan []string
+
+	if len(query.where) > 0 {
+		plan = append(plan, "Filter: "+joinExprs(query.where, " && "))
+	}
+
+	if len(query.groupBy) > 0 {
+		plan = append(plan, "GroupBy: "+joinExprs(query.groupBy, ", "))
+	} else if query.isGrouped() {
+		plan = append(plan, "GroupBy: all")
+	}
+
+	if len(query.selects) > 0 {
+		columns := make([]string, len(query.selects))
+		for index, selected := range query.selects {
+			columns[index] = selected.describe()
+		}
+		plan = append(plan, "Map: "+strings.Join(columns, ", "))
+	}
+
+	if len(query.orderBy) > 0 {
+		keys := make([]string, len(query.orderBy))
+		for index, order := range query.orderBy {
+			keys[index] = order.expr.String()
+			if order.desc {
+				keys[index] += " DESC"
+			}
+		}
+		plan = append(plan, "SortBy: "+strings.Join(keys, ", "))
+	}
+
+	if query.offset > 0 || query.limit >= 0 {
+		window := fmt.Sprintf("Slice: offset %d", query.offset)
+		if query.limit >= 0 {
+			window += fmt.Sprintf(", limit %d", query.limit)
+		}
+		plan = append(plan, window)
+	}
+
+	if len(plan) == 0 {
+		return "Scan"
+	}
+
+	return strings.Join(plan, "\n")
+}
+
+// Run executes the query and returns its rows, it never changes the source Seq
+func (query *QueryBuilder) Run() (rows Seq, err error) {
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			failure, ok := recovered.(*ExprError)
+			if !ok {
+				panic(recovered)
+			}
+			rows, err = nil, failure
+		}
+	}()
+
+	rows = createCopy(query.seq)
+
+	if len(query.where) > 0 {
+		rows = Filter(rows, query.createCondition())
+	}
+
+	if query.isGrouped() {
+		rows = Map(query.createGroups(rows), func(group, _, _ Object) Object {
+			return query.createRow(group.(Seq))
+		})
+	} else if len(query.selects) > 0 {
+		rows = Map(rows, func(current, _, _ Object) Object {
+			return query.createRow(Seq{current})
+		})
+	}
+
+	if len(query.orderBy) > 0 {
+		rows = SortBy(rows, query.createOrder())
+	}
+
+	return query.createSlice(rows), nil
+}
+
+// QuerySQL parses the query of SQL subset into QueryBuilder over given Seq:
+//
+//	SELECT columns [FROM name] [WHERE condition] [GROUP BY keys] [ORDER BY keys] [LIMIT n [OFFSET m]]
+//
+// SELECT * leaves elements as they are, FROM is ignored, keywords are case-insensitive, strings are quoted as in #Expr,
+// conditions support =, <>, AND, OR, NOT, IS NULL and IS NOT NULL along with the expression language,
+// NOTE: NOT binds tighter than comparisons, so NOT (a = b) needs parentheses
+func QuerySQL(seq Seq, sql string) (*QueryBuilder, error) {
+	clauses, err := createSQLClauses(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	query := Query(seq)
+
+	if columns := clauses["SELECT"]; strings.TrimSpace(columns) != "*" {
+		query.Select(createSQLList(columns)...)
+	}
+
+	if condition, ok := clauses["WHERE"]; ok {
+		query.Where(createSQLExpr(condition))
+	}
+
+	if keys, ok := clauses["GROUP BY"]; ok {
+		query.GroupBy(createSQLList(keys)...)
+	}
+
+	if keys, ok := clauses["ORDER BY"]; ok {
+		query.OrderBy(createSQLList(keys)...)
+	}
+
+	for _, clause := range []string{"LIMIT", "OFFSET"} {
+		value, ok := clauses[clause]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("ugo: query: %s expects a number, got %q", clause, strings.TrimSpace(value))
+		}
+
+		if clause == "LIMIT" {
+			query.Limit(n)
+		} else {
+			query.Offset(n)
+		}
+	}
+
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	return query, nil
+}
+
+/* private methods */
+// compile returns the expression, where fields of element might be used without it, or nil if it fails
+func (query *QueryBuilder) compile(source string) *Expr {
+	expr, err := createExpr(&exprParser{lexer: exprLexer{source: strings.TrimSpace(source)}, names: []string{"it"}, implicit: true})
+	if err != nil {
+		query.fail(err)
+		return nil
+	}
+	return expr
+}
+
+func (query *QueryBuilder) fail(err error) {
+	if query.err == nil {
+		query.err = err
+	}
+}
+
+func (query *QueryBuilder) isGrouped() bool {
+	if len(query.groupBy) > 0 {
+		return true
+	}
+
+	for _, selected := range query.selects {
+		if selected.aggregate != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (query *QueryBuilder) createCondition() Predicate {
+	return func(current, _, _ Object) bool {
+		for _, expr := range query.where {
+			result := expr.mustEval(current)
+			if result == nil {
+				return false
+			}
+
+			flag, ok := result.(bool)
+			if !ok {
+				panic(expr.createError(0, "condition must be bool, got %T", result))
+			}
+			if !flag {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// createGroups returns the groups of rows in order of their first appearance
+func (query *QueryBuilder) createGroups(rows Seq) Seq {
+	keyOf := func(current, _, _ Object) Object {
+		values := make(Seq, len(query.groupBy))
+		for index, expr := range query.groupBy {
+			values[index] = expr.mustEval(current)
+		}
+		return fmt.Sprintf("%#v", values)
+	}
+
+	if len(query.groupBy) == 0 {
+		return Seq{rows}
+	}
+
+	grouped := GroupBy(rows, keyOf)
+	groups := Seq{}
+
+	for index, row := range rows {
+		key := keyOf(row, index, rows)
+		if group, ok := grouped[key]; ok {
+			groups = append(groups, group)
+			delete(grouped, key)
+		}
+	}
+
+	return groups
+}
+
+// createRow returns the row of the result for the group of elements, single element is the group of one
+func (query *QueryBuilder) createRow(group Seq) Object {
+	row := make(map[string]Object, len(query.selects))
+
+	for _, selected := range query.selects {
+		if selected.aggregate == "" {
+			var first Object
+			if len(group) > 0 {
+				first = group[0]
+			}
+			row[selected.name] = selected.expr.mustEval(first)
+			continue
+		}
+
+		values := Seq{}
+		for _, current := range group {
+			if value := selected.expr.mustEval(current); value != nil {
+				values = append(values, value)
+			}
+		}
+
+		row[selected.name] = selected.createAggregate(values)
+	}
+
+	return row
+}
+
+func (query *QueryBuilder) createOrder() Comparator {
+	return func(left, right Object) int {
+		for _, order := range query.orderBy {
+			result := Compare(order.expr.mustEval(left), order.expr.mustEval(right))
+			if order.desc {
+				result = -result
+			}
+			if result != equal {
+				return result
+			}
+		}
+		return equal
+	}
+}
+
+func (query *QueryBuilder) createSlice(rows Seq) Seq {
+	if query.offset >= len(rows) {
+		return Seq{}
+	}
+
+	rows = rows[query.offset:]
+	if query.limit >= 0 && query.limit < len(rows) {
+		rows = rows[:query.limit]
+	}
+
+	return rows
+}
+
+func (selected querySelect) createAggregate(values Seq) Object {
+	if selected.aggregate == "count" {
+		return len(values)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	switch selected.aggregate {
+	case "min":
+		return Min(values, Compare)
+	case "max":
+		return Max(values, Compare)
+	}
+
+	var sum Object = 0
+	for _, value := range values {
+		result, err := exprArithmetic("+", sum, value)
+		if err != nil {
+			panic(selected.expr.createError(0, "%s: %v", selected.aggregate, err))
+		}
+		sum = result
+	}
+
+	if selected.aggregate == "sum" {
+		return sum
+	}
+
+	total, _ := toComparableFloat64(sum)
+	return total / float64(len(values))
+}
+
+func (selected querySelect) describe() string {
+	source := selected.expr.String()
+	if selected.aggregate != "" {
+		if selected.aggregate == "count" && source == "true" {
+			source = ""
+		}
+		source = selected.aggregate + "(" + source + ")"
+	}
+	return source + " AS " + selected.name
+}
+
+// createColumnName returns the last field of path or the whole source for the rest of expressions
+func createColumnName(source string) string {
+	source = strings.TrimSpace(source)
+
+	if queryPath.MatchString(source) {
+		return source[strings.LastIndex(source, ".")+1:]
+	}
+
+	if match := queryAggregate.FindStringSubmatch(source); match != nil {
+		return strings.ToLower(match[1]) + "(" + strings.TrimSpace(match[2]) + ")"
+	}
+
+	return source
+}
+
+func joinExprs(exprs []*Expr, separator string) string {
+	sources := make([]string, len(exprs))
+	for index, expr := range exprs {
+		sources[index] = expr.String()
+	}
+	return strings.Join(sources, separator)
+}
+
+// sqlKeywords are the clauses of SQL subset in the order they follow each other
+var sqlKeywords = []string{"SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "OFFSET"}
+
+// createSQLClauses splits the query into clauses by keywords outside of strings and parentheses
+func createSQLClauses(sql string) (map[string]string, error) {
+	sql = strings.TrimSpace(sql)
+	if matchSQLKeyword(sql, "SELECT") == 0 {
+		return nil, errors.New("ugo: query: expected SELECT at the start")
+	}
+
+	clauses := map[string]string{}
+	current, start, last := "", 0, -1
+
+	err := scanSQL(sql, func(pos int, depth int) int {
+		if depth > 0 || (pos > 0 && isSQLWordChar(sql[pos-1])) {
+			return 0
+		}
+
+		for order, keyword := range sqlKeywords {
+			length := matchSQLKeyword(sql[pos:], keyword)
+			if length == 0 {
+				continue
+			}
+
+			if order <= last {
+				return -1 - order
+			}
+
+			if current != "" {
+				clauses[current] = sql[start:pos]
+			}
+
+			current, start, last = keyword, pos+length, order
+			return length
+		}
+
+		return 0
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	clauses[current] = sql[start:]
+	return clauses, nil
+}
+
+// scanSQL calls cb for every position outside of strings with the depth of parentheses,
+// cb returns count of bytes to skip, or negative value to report the misplaced keyword
+func scanSQL(sql string, cb func(pos, depth int) int) error {
+	depth := 0
+
+	for pos := 0; pos < len(sql); {
+		switch sql[pos] {
+		case '\'', '"':
+			end := pos + 1
+			for end < len(sql) && sql[end] != sql[pos] {
+				if sql[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(sql) {
+				return fmt.Errorf("ugo: query: unterminated string at %d", pos+1)
+			}
+			pos = end + 1
+			continue
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+
+		skip := cb(pos, depth)
+		if skip < 0 {
+			return fmt.Errorf("ugo: query: misplaced %s at %d", sqlKeywords[-1-skip], pos+1)
+		}
+		if skip == 0 {
+			skip = 1
+		}
+		pos += skip
+	}
+
+	return nil
+}
+
+// matchSQLKeyword returns the length of keyword at the start of text, words of keyword may be split by any spaces
+func matchSQLKeyword(text, keyword string) int {
+	pos := 0
+
+	for index, word := range strings.Fields(keyword) {
+		if index > 0 {
+			spaces := pos
+			for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t' || text[pos] == '\n' || text[pos] == '\r') {
+				pos++
+			}
+			if pos == spaces {
+				return 0
+			}
+		}
+
+		if len(text)-pos < len(word) || !strings.EqualFold(text[pos:pos+len(word)], word) {
+			return 0
+		}
+		pos += len(word)
+	}
+
+	if pos < len(text) && isSQLWordChar(text[pos]) {
+		return 0
+	}
+
+	return pos
+}
+
+func isSQLWordChar(char byte) bool {
+	return char == '_' || char == '.' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+// createSQLList splits the list by commas outside of strings and parentheses and translates its items
+func createSQLList(list string) []string {
+	var items []string
+	start := 0
+
+	scanSQL(list, func(pos, depth int) int {
+		if depth == 0 && list[pos] == ',' {
+			items = append(items, createSQLExpr(list[start:pos]))
+			start = pos + 1
+		}
+		return 0
+	})
+
+	return append(items, createSQLExpr(list[start:]))
+}
+
+// sqlOperators are SQL operators and their replacements in the expression language, longer ones go first
+var sqlOperators = [][2]string{
+	{"IS NOT NULL", " != nil"},
+	{"IS NULL", " == nil"},
+	{"AND", "&&"},
+	{"OR", "||"},
+	{"NOT", "!"},
+	{"<>", "!="},
+	{"(*)", "()"},
+}
+
+// createSQLExpr translates SQL operators outside of strings into the expression language
+func createSQLExpr(sql string) string {
+	var expr strings.Builder
+	copied := 0
+
+	scanSQL(sql, func(pos, depth int) int {
+		wordStart := pos == 0 || !isSQLWordChar(sql[pos-1])
+
+		for _, operator := range sqlOperators {
+			length := matchSQLKeyword(sql[pos:], operator[0])
+			if !isSQLWordChar(operator[0][0]) {
+				length = 0
+				if strings.HasPrefix(sql[pos:], operator[0]) {
+					length = len(operator[0])
+				}
+			} else if !wordStart {
+				length = 0
+			}
+
+			if length > 0 {
+				expr.WriteString(sql[copied:pos])
+				expr.WriteString(operator[1])
+				copied = pos + length
+				return length
+			}
+		}
+
+		if sql[pos] == '=' && (pos == 0 || strings.IndexByte("<>!=", sql[pos-1]) < 0) && (pos+1 == len(sql) || sql[pos+1] != '=') {
+			expr.WriteString(sql[copied:pos])
+			expr.WriteString("==")
+			copied = pos + 1
+		}
+
+		return 0
+	})
+
+	expr.WriteString(sql[copied:])
+	return strings.TrimSpace(expr.String())
+}
@@ -0,0 +1,216 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+type queryPlayer struct {
+	Name  string
+	Team  string
+	Score int
+	Age   int
+}
+
+func TestQuery(t *testing.T) {
+	g := Goblin(t)
+
+	players := Seq{
+		queryPlayer{"ann", "red", 10, 31},
+		queryPlayer{"bob", "blue", 7, 17},
+		queryPlayer{"cid", "red", 4, 25},
+		queryPlayer{"dan", "green", 12, 40},
+		queryPlayer{"eve", "blue", 9, 22},
+	}
+
+	g.Describe("#Query()", func() {
+		g.It("Should filter, select, order and limit rows", func() {
+			rows, err := Query(players).
+				Where("Age > 18").
+				Select("Name", "Score * 2 as double").
+				OrderBy("double desc").
+				Limit(2).
+				Run()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{
+				map[string]Object{"Name": "dan", "double": 24},
+				map[string]Object{"Name": "ann", "double": 20},
+			})
+		})
+
+		g.It("Should group rows and aggregate them", func() {
+			rows, err := Query(players).
+				GroupBy("Team").
+				Select("Team", "count() as players", "sum(Score) as total", "avg(Age)", "max(Name) as last").
+				OrderBy("total desc", "Team").
+				Run()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{
+				map[string]Object{"Team": "blue", "players": 2, "total": 16, "avg(Age)": 19.5, "last": "eve"},
+				map[string]Object{"Team": "red", "players": 2, "total": 14, "avg(Age)": 28.0, "last": "cid"},
+				map[string]Object{"Team": "green", "players": 1, "total": 12, "avg(Age)": 40.0, "last": "dan"},
+			})
+		})
+
+		g.It("Should aggregate all rows without GroupBy", func() {
+			rows, err := Query(players).Where("Team == 'red' && Score > 100").Select("count(*)", "sum(Score)", "min(Age) as youngest").Run()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{map[string]Object{"count(*)": 0, "sum(Score)": nil, "youngest": nil}})
+		})
+
+		g.It("Should keep order of groups and work with maps", func() {
+			seq := Seq{
+				map[string]Object{"team": "red", "stats": map[string]Object{"goals": 2}},
+				map[string]Object{"team": "blue"},
+				map[string]Object{"team": "red", "stats": map[string]Object{"goals": 3}},
+			}
+
+			rows, err := Query(seq).GroupBy("team").Select("team", "sum(stats.goals) as goals", "count(stats) as played").Run()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{
+				map[string]Object{"team": "red", "goals": 5, "played": 2},
+				map[string]Object{"team": "blue", "goals": nil, "played": 0},
+			})
+		})
+
+		g.It("Should return elements as they are without Select and leave source untouched", func() {
+			rows, err := Query(players).OrderBy("Score").Offset(3).Run()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{players[0], players[3]})
+			g.Assert(players[0]).Equal(queryPlayer{"ann", "red", 10, 31})
+
+			rows, _ = Query(players).Offset(10).Run()
+			g.Assert(rows).Equal(Seq{})
+		})
+
+		g.It("Should return errors of building and running", func() {
+			_, err := Query(players).Where("Age >").Run()
+			g.Assert(err.Error()).Equal(`ugo: expression "Age >" at column 6: unexpected end of expression`)
+
+			_, err = Query(players).Where("Name").Run()
+			g.Assert(err.Error()).Equal(`ugo: expression "Name" at column 1: condition must be bool, got string`)
+
+			_, err = Query(players).Select("Height").Run()
+			g.Assert(err.Error()).Equal(`ugo: expression "Height" at column 1: ugo_test.queryPlayer has no exported field Height`)
+
+			_, err = Query(players).Select("sum()").Run()
+			g.Assert(err.Error()).Equal("ugo: query: sum needs an argument")
+
+			_, err = Query(players).GroupBy("Team").Select("sum(Name)").Run()
+			g.Assert(err == nil).IsFalse()
+
+			g.Assert(Query(players).Limit(-1).Err().Error()).Equal("ugo: query: negative limit -1")
+		})
+	})
+
+	g.Describe("#Explain()", func() {
+		g.It("Should describe the plan", func() {
+			plan := Query(players).
+				Where("Age > 18").
+				Where("Team != 'green'").
+				GroupBy("Team").
+				Select("Team", "count() as players", "sum(Score) as total").
+				OrderBy("total desc").
+				Limit(10).
+				Explain()
+
+			g.Assert(plan).Equal("Filter: Age > 18 && Team != 'green'\n" +
+				"GroupBy: Team\n" +
+				"Map: Team AS Team, count() AS players, sum(Score) AS total\n" +
+				"SortBy: total DESC\n" +
+				"Slice: offset 0, limit 10")
+
+			g.Assert(Query(players).Explain()).Equal("Scan")
+			g.Assert(Query(players).Select("max(Age)").Explain()).Equal("GroupBy: all\nMap: max(Age) AS max(Age)")
+		})
+	})
+
+	g.Describe("#QuerySQL()", func() {
+		g.It("Should run the query of SQL subset", func() {
+			query, err := QuerySQL(players, `
+				select Team, COUNT(*) as players, sum(Score) AS total
+				from players
+				where Age >= 18 and not (Team = 'green') or Name = "bob"
+				group   by Team
+				order by total DESC, Team
+				limit 5 offset 0`)
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(query.Explain()).Equal("Filter: Age >= 18 && ! (Team == 'green') || Name == \"bob\"\n" +
+				"GroupBy: Team\n" +
+				"Map: Team AS Team, count() AS players, sum(Score) AS total\n" +
+				"SortBy: total DESC, Team\n" +
+				"Slice: offset 0, limit 5")
+
+			rows, err := query.Run()
+			g.Assert(err == nil).IsTrue()
+			g.Assert(rows).Equal(Seq{
+				map[string]Object{"Team": "blue", "players": 2, "total": 16},
+				map[string]Object{"Team": "red", "players": 2, "total": 14},
+			})
+		})
+
+		g.It("Should handle select star, strings with keywords and null checks", func() {
+			seq := Seq{
+				map[string]Object{"note": "select from where", "id": 1},
+				map[string]Object{"id": 2},
+				map[string]Object{"note": "a <> b", "id": 3},
+			}
+
+			query, err := QuerySQL(seq, "SELECT * WHERE note IS NOT NULL AND note <> 'a <> b' ORDER BY id DESC")
+			g.Assert(err == nil).IsTrue()
+
+			rows, _ := query.Run()
+			g.Assert(rows).Equal(Seq{seq[0]})
+
+			query, _ = QuerySQL(seq, "SELECT id WHERE note IS NULL")
+			rows, _ = query.Run()
+			g.Assert(rows).Equal(Seq{map[string]Object{"id": 2}})
+		})
+
+		g.It("Should return errors for malformed queries", func() {
+			_, err := QuerySQL(players, "WHERE Age > 1")
+			g.Assert(err.Error()).Equal("ugo: query: expected SELECT at the start")
+
+			_, err = QuerySQL(players, "SELECT * LIMIT 1 WHERE Age > 1")
+			g.Assert(err.Error()).Equal("ugo: query: misplaced WHERE at 18")
+
+			_, err = QuerySQL(players, "SELECT * LIMIT ten")
+			g.Assert(err.Error()).Equal(`ugo: query: LIMIT expects a number, got "ten"`)
+
+			_, err = QuerySQL(players, "SELECT * WHERE Name = 'ann")
+			g.Assert(err.Error()).Equal("ugo: query: unterminated string at 23")
+
+			_, err = QuerySQL(players, "SELECT Name +")
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,109 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	crypto "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Rand is the source of random numbers, used in Shuffle, Random and sampling functions,
+// *rand.Rand from math/rand satisfies it
+type Rand interface {
+	Intn(n int) int   // Intn returns a random number in [0, n), it panics if n <= 0
+	Float64() float64 // Float64 returns a random number in [0.0, 1.0)
+}
+
+// CryptoRand is the Rand, which reads random numbers from crypto/rand,
+// it's slower, but its numbers are unpredictable
+type CryptoRand struct{}
+
+// NewRand creates a new seeded Rand, which returns the same numbers for the same seed
+// NOTE: it's not safe to use it from many goroutines
+func NewRand(seed int64) Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// Intn returns a random number in [0, n), it panics if n <= 0
+func (CryptoRand) Intn(n int) int {
+	if n <= 0 {
+		panic("ugo: invalid argument to Intn")
+	}
+
+	bound := uint64(n)
+	limit := ^uint64(0) - ^uint64(0)%bound
+
+	for {
+		if value := readCryptoUint64(); value < limit {
+			return int(value % bound)
+		}
+	}
+}
+
+// Float64 returns a random number in [0.0, 1.0)
+func (CryptoRand) Float64() float64 {
+	return float64(readCryptoUint64()>>11) / (1 << 53)
+}
+
+/* private methods */
+// defaultRand is the Rand, used when no other one is passed,
+// it's seeded once and is safe to use from many goroutines
+var defaultRand Rand = &lockedRand{source: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// lockedRand is the Rand, guarded by mutex
+type lockedRand struct {
+	mutex  sync.Mutex
+	source *rand.Rand
+}
+
+func (locked *lockedRand) Intn(n int) int {
+	locked.mutex.Lock()
+	defer locked.mutex.Unlock()
+	return locked.source.Intn(n)
+}
+
+func (locked *lockedRand) Float64() float64 {
+	locked.mutex.Lock()
+	defer locked.mutex.Unlock()
+	return locked.source.Float64()
+}
+
+// fixRand returns given Rand or the default one if it's nil
+func fixRand(rng Rand) Rand {
+	if rng == nil {
+		return defaultRand
+	}
+	return rng
+}
+
+// readCryptoUint64 returns a random number, read from crypto/rand
+func readCryptoUint64() uint64 {
+	var buf [8]byte
+	if _, err := crypto.Read(buf[:]); err != nil {
+		panic(err)
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
@@ -0,0 +1,114 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math/rand"
+	"testing"
+)
+
+func TestRand(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	inSeq := Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}
+
+	g.Describe("#NewRand()", func() {
+		g.It("Should return the same numbers for the same seed", func() {
+			left := NewRand(42)
+			right := NewRand(42)
+
+			for i := 0; i < 100; i++ {
+				g.Assert(left.Intn(1000)).Equal(right.Intn(1000))
+				g.Assert(left.Float64()).Equal(right.Float64())
+			}
+		})
+	})
+
+	g.Describe("#CryptoRand", func() {
+		g.It("Should return numbers in given bounds", func() {
+			rng := CryptoRand{}
+			seen := make(map[int]bool)
+
+			for i := 0; i < 1000; i++ {
+				value := rng.Intn(5)
+				seen[value] = true
+				g.Assert(value >= 0 && value < 5).IsTrue()
+
+				float := rng.Float64()
+				g.Assert(float >= 0 && float < 1).IsTrue()
+			}
+
+			g.Assert(len(seen)).Equal(5)
+		})
+
+		g.It("Should panic for not positive bound", func() {
+			defer func() { g.Assert(recover() != nil).IsTrue() }()
+			CryptoRand{}.Intn(0)
+		})
+	})
+
+	g.Describe("#ShuffleWith()", func() {
+		g.It("Should shuffle Seq reproducibly with seeded Rand", func() {
+			left := ShuffleWith(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}, NewRand(7))
+			right := ShuffleWith(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}, rand.New(rand.NewSource(7)))
+
+			g.Assert(left).Equal(right)
+			g.Assert(EqualsNotStrict(left, inSeq, intComparator)).IsTrue()
+			g.Assert(EqualsNotStrict(ShuffleWith(Seq{1, 2, 3}, nil), Seq{1, 2, 3}, intComparator)).IsTrue()
+			g.Assert(EqualsNotStrict(ShuffleWith(Seq{1, 2, 3}, CryptoRand{}), Seq{1, 2, 3}, intComparator)).IsTrue()
+			g.Assert(ShuffleWith(nil, NewRand(7))).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#ShuffledCopyWith()", func() {
+		g.It("Should return reproducibly shuffled copy of Seq", func() {
+			g.Assert(ShuffledCopyWith(inSeq, NewRand(7))).Equal(ShuffledCopyWith(inSeq, NewRand(7)))
+			g.Assert(inSeq).Equal(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17})
+			g.Assert(ShuffledCopyWith(nil, nil)).Equal(Seq{})
+		})
+
+		g.It("Should be used by chaining wrapper", func() {
+			g.Assert(Chain(inSeq).ShuffleWith(NewRand(3)).Value()).Equal(ShuffledCopyWith(inSeq, NewRand(3)))
+			g.Assert(inSeq).Equal(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17})
+		})
+	})
+
+	g.Describe("#RandomWith()", func() {
+		g.It("Should return reproducible random number from min to max", func() {
+			left := NewRand(11)
+			right := NewRand(11)
+
+			for i := 0; i < 100; i++ {
+				value := RandomWith(-5, 5, left)
+				g.Assert(value).Equal(RandomWith(-5, 5, right))
+				g.Assert(value >= -5 && value <= 5).IsTrue()
+			}
+
+			g.Assert(RandomWith(3, 3, left)).Equal(0)
+			g.Assert(RandomWith(0, 10, nil) <= 10).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,295 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"math"
+	"reflect"
+)
+
+// WeightedSampler is the special struct, which picks elements with probability
+// proportional to their weights, it takes O(1) operations to pick one element
+type WeightedSampler struct {
+	seq   Seq       // seq is the slice of elements with positive weights
+	prob  []float64 // prob is the probability to keep the element of the alias table column
+	alias []int     // alias is the position of the element, which fills the rest of the column
+}
+
+// Reservoir is the special struct, which keeps the uniform sample of fixed size
+// from the stream of elements of unknown length
+type Reservoir struct {
+	seq  Seq  // seq is the current sample
+	size int  // size is the max count of sampled elements
+	seen int  // seen is the count of elements added so far
+	rng  Rand // rng is the source of random numbers
+}
+
+// Sample returns n random elements of slice without replacement, e. g. every position is picked once,
+// it takes O(n) operations and doesn't copy the whole slice,
+// if Rand is not passed, it uses the default one
+func Sample(seq Seq, n int, rng Rand) Seq {
+	if IsEmpty(seq) || n <= 0 {
+		return Seq{}
+	}
+	if n > len(seq) {
+		n = len(seq)
+	}
+
+	rng = fixRand(rng)
+	length := len(seq)
+	swapped := make(map[int]int, n)
+	result := NewSeq(n)
+
+	position := func(index int) int {
+		if moved, ok := swapped[index]; ok {
+			return moved
+		}
+		return index
+	}
+
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(length-i)
+		result[i] = seq[position(j)]
+		swapped[j] = position(i)
+	}
+
+	return result
+}
+
+// SampleWithReplacement returns n random elements of slice, the same position can be picked many times,
+// if Rand is not passed, it uses the default one
+func SampleWithReplacement(seq Seq, n int, rng Rand) Seq {
+	if IsEmpty(seq) || n <= 0 {
+		return Seq{}
+	}
+
+	rng = fixRand(rng)
+	result := NewSeq(n)
+
+	for i := range result {
+		result[i] = seq[rng.Intn(len(seq))]
+	}
+
+	return result
+}
+
+// WeightedSample returns n random elements of slice with replacement,
+// the probability to pick an element is proportional to its weight, calculated in Callback,
+// if Rand is not passed, it uses the default one
+func WeightedSample(seq Seq, cb Callback, n int, rng Rand) Seq {
+	return NewWeightedSampler(seq, cb).Sample(n, rng)
+}
+
+// NewWeightedSampler creates a new WeightedSampler, building its alias table in O(n) operations,
+// weights are numbers, calculated in Callback, elements with not positive weights are never picked
+func NewWeightedSampler(seq Seq, cb Callback) *WeightedSampler {
+	sampler := &WeightedSampler{seq: NewSeq(0)}
+	if cb == nil {
+		return sampler
+	}
+
+	weights := make([]float64, 0, len(seq))
+	total := 0.0
+
+	for index, val := range seq {
+		weight, ok := toFloat64(cb(val, index, seq))
+		if !ok || math.IsNaN(weight) || math.IsInf(weight, 0) || weight <= 0 {
+			continue
+		}
+
+		sampler.seq = append(sampler.seq, val)
+		weights = append(weights, weight)
+		total += weight
+	}
+
+	sampler.prob, sampler.alias = createAliasTable(weights, total)
+	return sampler
+}
+
+// Len returns the count of elements, which can be picked
+func (sampler *WeightedSampler) Len() int {
+	return len(sampler.seq)
+}
+
+// Next returns one random element, or nil if there are no elements to pick,
+// if Rand is not passed, it uses the default one
+func (sampler *WeightedSampler) Next(rng Rand) Object {
+	if len(sampler.seq) == 0 {
+		return nil
+	}
+
+	rng = fixRand(rng)
+	column := rng.Intn(len(sampler.seq))
+
+	if rng.Float64() < sampler.prob[column] {
+		return sampler.seq[column]
+	}
+	return sampler.seq[sampler.alias[column]]
+}
+
+// Sample returns n random elements with replacement,
+// if Rand is not passed, it uses the default one
+func (sampler *WeightedSampler) Sample(n int, rng Rand) Seq {
+	if len(sampler.seq) == 0 || n <= 0 {
+		return Seq{}
+	}
+
+	rng = fixRand(rng)
+	result := NewSeq(n)
+
+	for i := range result {
+		result[i] = sampler.Next(rng)
+	}
+
+	return result
+}
+
+// NewReservoir creates a new empty Reservoir, which keeps up to size elements,
+// if Rand is not passed, it uses the default one
+func NewReservoir(size int, rng Rand) *Reservoir {
+	if size < 0 {
+		size = 0
+	}
+	return &Reservoir{seq: make(Seq, 0, size), size: size, rng: fixRand(rng)}
+}
+
+// Add passes the next element of the stream to Reservoir,
+// so every element seen so far has the same chance to be in the sample
+func (reservoir *Reservoir) Add(target Object) {
+	reservoir.seen++
+
+	if len(reservoir.seq) < reservoir.size {
+		reservoir.seq = append(reservoir.seq, target)
+		return
+	}
+
+	if j := reservoir.rng.Intn(reservoir.seen); j < reservoir.size {
+		reservoir.seq[j] = target
+	}
+}
+
+// Seen returns the count of elements added to Reservoir
+func (reservoir *Reservoir) Seen() int {
+	return reservoir.seen
+}
+
+// Seq returns the copy of current sample
+func (reservoir *Reservoir) Seq() Seq {
+	return createCopy(reservoir.seq)
+}
+
+// SampleIterator returns n random elements, taken from Iterator without replacement,
+// it goes through Iterator once and keeps only n elements in memory
+func SampleIterator(it Iterator, n int, rng Rand) Seq {
+	reservoir := NewReservoir(n, rng)
+	if it == nil {
+		return reservoir.Seq()
+	}
+
+	it(func(current Object) bool {
+		reservoir.Add(current)
+		return true
+	})
+
+	return reservoir.Seq()
+}
+
+// SampleChan returns n random elements, received from channel without replacement,
+// it reads the channel until it's closed and keeps only n elements in memory
+func SampleChan(ch <-chan Object, n int, rng Rand) Seq {
+	reservoir := NewReservoir(n, rng)
+	if ch == nil {
+		return reservoir.Seq()
+	}
+
+	for current := range ch {
+		reservoir.Add(current)
+	}
+
+	return reservoir.Seq()
+}
+
+/* private methods */
+// createAliasTable returns columns of the alias table, built from weights by Vose's algorithm
+func createAliasTable(weights []float64, total float64) ([]float64, []int) {
+	length := len(weights)
+	prob := make([]float64, length)
+	alias := make([]int, length)
+	small := make([]int, 0, length)
+	large := make([]int, 0, length)
+
+	for index, weight := range weights {
+		prob[index] = weight * float64(length) / total
+		if prob[index] < 1 {
+			small = append(small, index)
+		} else {
+			large = append(large, index)
+		}
+	}
+
+	for len(small) != 0 && len(large) != 0 {
+		less, more := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+
+		alias[less] = more
+		prob[more] -= 1 - prob[less]
+
+		if prob[more] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, more)
+		}
+	}
+
+	for _, index := range large {
+		prob[index] = 1
+	}
+	for _, index := range small {
+		prob[index] = 1
+	}
+
+	return prob, alias
+}
+
+// toFloat64 returns the value of any number type as float64 and true,
+// or zero and false if value is not a number
+func toFloat64(value Object) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case float64:
+		return number, true
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(reflected.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), true
+	}
+
+	return 0, false
+}
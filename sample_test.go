@@ -0,0 +1,182 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	inSeq := Seq{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	g.Describe("#Sample()", func() {
+		g.It("Should return n elements on different positions", func() {
+			rng := NewRand(1)
+
+			for i := 0; i < 100; i++ {
+				sample := Sample(inSeq, 4, rng)
+
+				g.Assert(len(sample)).Equal(4)
+				g.Assert(len(Uniq(sample, intComparator))).Equal(4)
+				g.Assert(len(Difference(sample, inSeq, intComparator))).Equal(0)
+			}
+
+			g.Assert(EqualsNotStrict(Sample(inSeq, 20, rng), inSeq, intComparator)).IsTrue()
+			g.Assert(Sample(inSeq, 0, rng)).Equal(Seq{})
+			g.Assert(Sample(nil, 3, rng)).Equal(Seq{})
+			g.Assert(len(Sample(inSeq, 3, nil))).Equal(3)
+			g.Assert(inSeq).Equal(Seq{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+		})
+
+		g.It("Should be reproducible and pick every element equally often", func() {
+			g.Assert(Sample(inSeq, 5, NewRand(9))).Equal(Sample(inSeq, 5, NewRand(9)))
+
+			rng := NewRand(2)
+			counts := make([]int, len(inSeq))
+			for i := 0; i < 10000; i++ {
+				for _, value := range Sample(inSeq, 3, rng) {
+					counts[value.(int)]++
+				}
+			}
+			for _, count := range counts {
+				g.Assert(count > 2700 && count < 3300).IsTrue()
+			}
+		})
+	})
+
+	g.Describe("#SampleWithReplacement()", func() {
+		g.It("Should return n elements of Seq", func() {
+			sample := SampleWithReplacement(Seq{1, 2}, 50, NewRand(3))
+
+			g.Assert(len(sample)).Equal(50)
+			g.Assert(EqualsNotStrict(Uniq(sample, intComparator), Seq{1, 2}, intComparator)).IsTrue()
+			g.Assert(SampleWithReplacement(nil, 5, nil)).Equal(Seq{})
+			g.Assert(SampleWithReplacement(inSeq, -1, nil)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#WeightedSample()", func() {
+		g.It("Should pick elements proportionally to their weights", func() {
+			weights := Seq{"never", "rare", "often"}
+			weightCb := func(cur, index, _ Object) Object { return index.(int) * index.(int) }
+			counts := CountBy(WeightedSample(weights, weightCb, 10000, NewRand(4)), func(cur, _, _ Object) Object { return cur })
+
+			g.Assert(counts["never"]).Equal(0)
+			g.Assert(counts["rare"] + counts["often"]).Equal(10000)
+			g.Assert(counts["rare"] > 1800 && counts["rare"] < 2200).IsTrue()
+		})
+
+		g.It("Should return empty Seq if nothing can be picked", func() {
+			zeroCb := func(_, _, _ Object) Object { return 0.0 }
+			stringCb := func(_, _, _ Object) Object { return "weight" }
+
+			g.Assert(WeightedSample(inSeq, zeroCb, 5, nil)).Equal(Seq{})
+			g.Assert(WeightedSample(inSeq, stringCb, 5, nil)).Equal(Seq{})
+			g.Assert(WeightedSample(inSeq, nil, 5, nil)).Equal(Seq{})
+			g.Assert(WeightedSample(nil, zeroCb, 5, nil)).Equal(Seq{})
+			g.Assert(NewWeightedSampler(inSeq, zeroCb).Next(nil)).Equal(nil)
+		})
+
+		g.It("Should accept weights of any number type", func() {
+			sampler := NewWeightedSampler(Seq{"a", "b"}, func(cur, _, _ Object) Object {
+				if cur == "a" {
+					return uint8(1)
+				}
+				return float32(0)
+			})
+
+			g.Assert(sampler.Len()).Equal(1)
+			g.Assert(sampler.Sample(3, nil)).Equal(Seq{"a", "a", "a"})
+		})
+	})
+
+	g.Describe("#Reservoir", func() {
+		g.It("Should keep uniform sample of the stream", func() {
+			rng := NewRand(5)
+			counts := make([]int, 10)
+
+			for i := 0; i < 10000; i++ {
+				reservoir := NewReservoir(2, rng)
+				for value := 0; value < 10; value++ {
+					reservoir.Add(value)
+				}
+
+				g.Assert(reservoir.Seen()).Equal(10)
+				for _, value := range reservoir.Seq() {
+					counts[value.(int)]++
+				}
+			}
+
+			for _, count := range counts {
+				g.Assert(count > 1800 && count < 2200).IsTrue()
+			}
+		})
+
+		g.It("Should keep every element of short stream", func() {
+			reservoir := NewReservoir(5, nil)
+			reservoir.Add(1)
+			reservoir.Add(2)
+
+			g.Assert(reservoir.Seq()).Equal(Seq{1, 2})
+			g.Assert(NewReservoir(-1, nil).Seq()).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#SampleIterator() and #SampleChan()", func() {
+		g.It("Should sample streamed elements", func() {
+			it := func(yield func(Object) bool) {
+				for i := 0; i < 1000; i++ {
+					if !yield(i) {
+						return
+					}
+				}
+			}
+			ch := make(chan Object)
+			go func() {
+				for i := 0; i < 1000; i++ {
+					ch <- i
+				}
+				close(ch)
+			}()
+
+			g.Assert(len(SampleIterator(it, 10, NewRand(6)))).Equal(10)
+			g.Assert(SampleIterator(it, 10, NewRand(6))).Equal(SampleIterator(it, 10, NewRand(6)))
+			g.Assert(len(Uniq(SampleChan(ch, 10, nil), intComparator))).Equal(10)
+			g.Assert(SampleIterator(nil, 3, nil)).Equal(Seq{})
+			g.Assert(SampleChan(nil, 3, nil)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("Chaining", func() {
+		g.It("Should sample Seq by chaining", func() {
+			g.Assert(Chain(inSeq).Sample(3, NewRand(8)).Value()).Equal(Sample(inSeq, 3, NewRand(8)))
+			g.Assert(Chain(inSeq).SampleWithReplacement(3, NewRand(8)).Value()).Equal(SampleWithReplacement(inSeq, 3, NewRand(8)))
+			g.Assert(Chain(inSeq).WeightedSample(func(_, _, _ Object) Object { return 1 }, 3, NewRand(8)).Value()).Equal(WeightedSample(inSeq, func(_, _, _ Object) Object { return 1 }, 3, NewRand(8)))
+		})
+	})
+}
@@ -0,0 +1,613 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Set is the special struct, containing only unique elements,
+// it's hash-backed for comparable values and tree-backed for values ordered by Comparator,
+// the zero Set is the empty hash-backed one
+type Set struct {
+	items counter // items is the storage, which counts every element once
+}
+
+// MultiSet is the special struct, which counts the occurrences of equal elements,
+// it's hash-backed for comparable values and tree-backed for values ordered by Comparator,
+// the zero MultiSet is the empty hash-backed one
+type MultiSet struct {
+	items counter // items is the storage of elements with their counts
+	size  int     // size is the total count of elements
+}
+
+// NewSet creates a new hash-backed Set from given slice
+// NOTE: elements should be comparable, e. g. they're able to be map keys
+func NewSet(seq Seq) *Set {
+	return createSet(seq, hashCounter{})
+}
+
+// NewSetBy creates a new tree-backed Set from given slice, elements are ordered by Comparator,
+// if Comparator is not passed, it falls back to #NewSet
+func NewSetBy(seq Seq, cb Comparator) *Set {
+	if cb == nil {
+		return NewSet(seq)
+	}
+	return createSet(seq, &treeCounter{cb: cb})
+}
+
+// Add puts an element into the Set, returns false if it's already there
+func (set *Set) Add(target Object) bool {
+	if set.storage().count(target) != 0 {
+		return false
+	}
+	set.storage().add(target, 1)
+	return true
+}
+
+// Remove takes an element out of the Set, returns false if there was no such element
+func (set *Set) Remove(target Object) bool {
+	if set.storage().count(target) == 0 {
+		return false
+	}
+	set.storage().remove(target, 1)
+	return true
+}
+
+// Has returns true if the Set contains given element
+func (set *Set) Has(target Object) bool {
+	return set.storage().count(target) != 0
+}
+
+// Len returns the count of elements in the Set
+func (set *Set) Len() int {
+	return set.storage().distinct()
+}
+
+// Each calls cb Action on each element, tree-backed Set goes through them in sorted order
+func (set *Set) Each(cb Action) {
+	if cb == nil {
+		return
+	}
+
+	index := 0
+	set.storage().each(func(target Object, _ int) {
+		cb(target, index, set)
+		index++
+	})
+}
+
+// Seq returns the slice of Set elements, tree-backed Set returns them sorted
+func (set *Set) Seq() Seq {
+	result := make(Seq, 0, set.Len())
+	set.storage().each(func(target Object, _ int) { result = append(result, target) })
+	return result
+}
+
+// Union returns a new Set, which contains elements of both Sets
+// NOTE: elements of both Sets are compared the way the Set does it (see #Set.Intersect)
+func (set *Set) Union(other *Set) *Set {
+	result := createSet(set.Seq(), set.storage().empty())
+	set.aligned(other).each(func(target Object, _ int) { result.Add(target) })
+	return result
+}
+
+// Intersect returns a new Set, which contains elements present in both Sets
+// NOTE: if one Set is hash-backed and another is tree-backed, elements of the other one are compared
+// the way the Set does it, it panics if the Set is hash-backed and the other one has non-comparable elements
+func (set *Set) Intersect(other *Set) *Set {
+	result := &Set{items: set.storage().empty()}
+	others := set.aligned(other)
+	set.storage().each(func(target Object, _ int) {
+		if others.count(target) != 0 {
+			result.Add(target)
+		}
+	})
+	return result
+}
+
+// Difference returns a new Set, which contains elements that are not present in the other Set
+// NOTE: elements of both Sets are compared the way the Set does it (see #Set.Intersect)
+func (set *Set) Difference(other *Set) *Set {
+	result := &Set{items: set.storage().empty()}
+	others := set.aligned(other)
+	set.storage().each(func(target Object, _ int) {
+		if others.count(target) == 0 {
+			result.Add(target)
+		}
+	})
+	return result
+}
+
+// SymmetricDifference returns a new Set, which contains elements present in only one of the Sets
+// NOTE: elements of both Sets are compared the way the Set does it (see #Set.Intersect)
+func (set *Set) SymmetricDifference(other *Set) *Set {
+	result := set.Difference(other)
+	set.aligned(other).each(func(target Object, _ int) {
+		if !set.Has(target) {
+			result.Add(target)
+		}
+	})
+	return result
+}
+
+// IsSubset returns true if every element of the Set is present in the other Set
+// NOTE: elements of both Sets are compared the way the Set does it (see #Set.Intersect)
+func (set *Set) IsSubset(other *Set) bool {
+	isSubset := true
+	others := set.aligned(other)
+	set.storage().each(func(target Object, _ int) {
+		isSubset = isSubset && others.count(target) != 0
+	})
+	return isSubset
+}
+
+// NewMultiSet creates a new hash-backed MultiSet from given slice
+// NOTE: elements should be comparable, e. g. they're able to be map keys
+func NewMultiSet(seq Seq) *MultiSet {
+	return createMultiSet(seq, hashCounter{})
+}
+
+// NewMultiSetBy creates a new tree-backed MultiSet from given slice, elements are ordered by Comparator,
+// if Comparator is not passed, it falls back to #NewMultiSet
+func NewMultiSetBy(seq Seq, cb Comparator) *MultiSet {
+	if cb == nil {
+		return NewMultiSet(seq)
+	}
+	return createMultiSet(seq, &treeCounter{cb: cb})
+}
+
+// Add puts an element into the MultiSet and returns its count
+func (multi *MultiSet) Add(target Object) int {
+	multi.size++
+	return multi.storage().add(target, 1)
+}
+
+// Remove takes one occurrence of an element out of the MultiSet,
+// returns false if there was no such element
+func (multi *MultiSet) Remove(target Object) bool {
+	if multi.storage().count(target) == 0 {
+		return false
+	}
+	multi.size--
+	multi.storage().remove(target, 1)
+	return true
+}
+
+// Count returns the count of occurrences of given element
+func (multi *MultiSet) Count(target Object) int {
+	return multi.storage().count(target)
+}
+
+// Has returns true if the MultiSet contains given element
+func (multi *MultiSet) Has(target Object) bool {
+	return multi.storage().count(target) != 0
+}
+
+// Len returns the total count of elements in the MultiSet
+func (multi *MultiSet) Len() int {
+	return multi.size
+}
+
+// Distinct returns the count of unique elements in the MultiSet
+func (multi *MultiSet) Distinct() int {
+	return multi.storage().distinct()
+}
+
+// Each calls cb Action on each unique element, passing its count instead of index,
+// tree-backed MultiSet goes through them in sorted order
+func (multi *MultiSet) Each(cb Action) {
+	if cb == nil {
+		return
+	}
+	multi.storage().each(func(target Object, count int) { cb(target, count, multi) })
+}
+
+// Seq returns the slice of MultiSet elements, every element is repeated as many times as it was counted
+func (multi *MultiSet) Seq() Seq {
+	result := make(Seq, 0, multi.size)
+	multi.storage().each(func(target Object, count int) {
+		for i := 0; i < count; i++ {
+			result = append(result, target)
+		}
+	})
+	return result
+}
+
+// Set returns a new Set of unique elements of the MultiSet
+func (multi *MultiSet) Set() *Set {
+	result := &Set{items: multi.storage().empty()}
+	multi.storage().each(func(target Object, _ int) { result.Add(target) })
+	return result
+}
+
+// Union returns a new MultiSet, which counts every element as the max of both counts
+func (multi *MultiSet) Union(other *MultiSet) *MultiSet {
+	return multi.combine(other, func(left, right int) int {
+		if left > right {
+			return left
+		}
+		return right
+	})
+}
+
+// Intersect returns a new MultiSet, which counts every element as the min of both counts
+func (multi *MultiSet) Intersect(other *MultiSet) *MultiSet {
+	return multi.combine(other, func(left, right int) int {
+		if left < right {
+			return left
+		}
+		return right
+	})
+}
+
+// Difference returns a new MultiSet, which counts every element
+// as its count in the MultiSet minus its count in the other one
+func (multi *MultiSet) Difference(other *MultiSet) *MultiSet {
+	return multi.combine(other, func(left, right int) int { return left - right })
+}
+
+// SymmetricDifference returns a new MultiSet, which counts every element
+// as the absolute difference of both counts
+func (multi *MultiSet) SymmetricDifference(other *MultiSet) *MultiSet {
+	return multi.combine(other, func(left, right int) int {
+		if left < right {
+			return right - left
+		}
+		return left - right
+	})
+}
+
+// IsSubset returns true if every element of the MultiSet
+// is present in the other one at least the same count of times
+func (multi *MultiSet) IsSubset(other *MultiSet) bool {
+	isSubset := true
+	others := multi.aligned(other)
+	multi.storage().each(func(target Object, count int) {
+		isSubset = isSubset && count <= others.count(target)
+	})
+	return isSubset
+}
+
+/* private methods */
+// combine returns a new MultiSet, which counts every element of both MultiSets
+// as the result of cb, called with counts of this element in each of them
+func (multi *MultiSet) combine(other *MultiSet, cb func(left, right int) int) *MultiSet {
+	result := &MultiSet{items: multi.storage().empty()}
+	put := func(target Object, count int) {
+		if count > 0 && !result.Has(target) {
+			result.storage().add(target, count)
+			result.size += count
+		}
+	}
+
+	others := multi.aligned(other)
+	multi.storage().each(func(target Object, count int) { put(target, cb(count, others.count(target))) })
+	others.each(func(target Object, count int) { put(target, cb(multi.Count(target), count)) })
+
+	return result
+}
+
+// aligned returns the storage of the other Set in the same kind as the storage of Set, so elements
+// of both are compared the same way, the nil Set is the empty one
+func (set *Set) aligned(other *Set) counter {
+	if other == nil {
+		return set.storage().empty()
+	}
+	return alignCounter(set.storage(), other.storage())
+}
+
+// aligned returns the storage of the other MultiSet in the same kind as the storage of MultiSet,
+// so elements of both are compared the same way, the nil MultiSet is the empty one
+func (multi *MultiSet) aligned(other *MultiSet) counter {
+	if other == nil {
+		return multi.storage().empty()
+	}
+	return alignCounter(multi.storage(), other.storage())
+}
+
+// storage returns the storage of Set, creating the hash-backed one for the zero Set
+func (set *Set) storage() counter {
+	if set.items == nil {
+		set.items = hashCounter{}
+	}
+	return set.items
+}
+
+// storage returns the storage of MultiSet, creating the hash-backed one for the zero MultiSet
+func (multi *MultiSet) storage() counter {
+	if multi.items == nil {
+		multi.items = hashCounter{}
+	}
+	return multi.items
+}
+
+// counter is the storage, which counts occurrences of equal elements
+type counter interface {
+	add(target Object, times int) int    // add returns the count after adding
+	remove(target Object, times int) int // remove returns the count after removing
+	count(target Object) int
+	distinct() int
+	each(cb func(target Object, count int))
+	empty() counter // empty returns a new storage of the same kind
+}
+
+// alignCounter returns other as is if it's of the same kind as items,
+// otherwise it copies its elements into the new storage of the same kind as items
+func alignCounter(items, other counter) counter {
+	_, isHash := items.(hashCounter)
+	if _, isOtherHash := other.(hashCounter); isHash == isOtherHash {
+		return other
+	}
+
+	result := items.empty()
+	other.each(func(target Object, count int) {
+		if isHash && !isHashable(reflect.ValueOf(target)) {
+			panic(fmt.Sprintf("ugo: can't put non-comparable %T into the hash-backed set", target))
+		}
+		result.add(target, count)
+	})
+	return result
+}
+
+// hashCounter is the counter, backed by map
+type hashCounter map[Object]int
+
+func (items hashCounter) add(target Object, times int) int {
+	items[target] += times
+	return items[target]
+}
+
+func (items hashCounter) remove(target Object, times int) int {
+	count := items[target] - times
+	if count <= 0 {
+		delete(items, target)
+		return 0
+	}
+	items[target] = count
+	return count
+}
+
+func (items hashCounter) count(target Object) int {
+	return items[target]
+}
+
+func (items hashCounter) distinct() int {
+	return len(items)
+}
+
+func (items hashCounter) each(cb func(target Object, count int)) {
+	for target, count := range items {
+		cb(target, count)
+	}
+}
+
+func (items hashCounter) empty() counter {
+	return hashCounter{}
+}
+
+// treeCounter is the counter, backed by AVL tree ordered by Comparator
+type treeCounter struct {
+	root *treeNode
+	size int
+	cb   Comparator
+}
+
+// treeNode is the node of AVL tree, which keeps the count of equal elements
+type treeNode struct {
+	value       Object
+	count       int
+	height      int
+	left, right *treeNode
+}
+
+func (items *treeCounter) add(target Object, times int) int {
+	var count int
+	items.root, count = createTreeInsert(items.root, target, times, items.cb)
+	if count == times {
+		items.size++
+	}
+	return count
+}
+
+func (items *treeCounter) remove(target Object, times int) int {
+	node := items.find(target)
+	if node == nil {
+		return 0
+	}
+	if node.count > times {
+		node.count -= times
+		return node.count
+	}
+
+	items.root = createTreeDelete(items.root, target, items.cb)
+	items.size--
+	return 0
+}
+
+func (items *treeCounter) count(target Object) int {
+	if node := items.find(target); node != nil {
+		return node.count
+	}
+	return 0
+}
+
+func (items *treeCounter) distinct() int {
+	return items.size
+}
+
+func (items *treeCounter) each(cb func(target Object, count int)) {
+	stack := make([]*treeNode, 0)
+	node := items.root
+
+	for node != nil || len(stack) != 0 {
+		for ; node != nil; node = node.left {
+			stack = append(stack, node)
+		}
+		node = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cb(node.value, node.count)
+		node = node.right
+	}
+}
+
+func (items *treeCounter) empty() counter {
+	return &treeCounter{cb: items.cb}
+}
+
+func (items *treeCounter) find(target Object) *treeNode {
+	node := items.root
+	for node != nil {
+		if diff := items.cb(target, node.value); diff < 0 {
+			node = node.left
+		} else if diff > 0 {
+			node = node.right
+		} else {
+			return node
+		}
+	}
+	return nil
+}
+
+// createSet returns a new Set, filled with elements of given slice
+func createSet(seq Seq, items counter) *Set {
+	set := &Set{items: items}
+	for _, value := range seq {
+		set.Add(value)
+	}
+	return set
+}
+
+// createMultiSet returns a new MultiSet, filled with elements of given slice
+func createMultiSet(seq Seq, items counter) *MultiSet {
+	multi := &MultiSet{items: items}
+	for _, value := range seq {
+		multi.Add(value)
+	}
+	return multi
+}
+
+// createTreeInsert returns the balanced tree with target counted given times,
+// and the count of target after insertion
+func createTreeInsert(node *treeNode, target Object, times int, cb Comparator) (*treeNode, int) {
+	var count int
+
+	if node == nil {
+		return &treeNode{value: target, count: times, height: 1}, times
+	}
+
+	if diff := cb(target, node.value); diff < 0 {
+		node.left, count = createTreeInsert(node.left, target, times, cb)
+	} else if diff > 0 {
+		node.right, count = createTreeInsert(node.right, target, times, cb)
+	} else {
+		node.count += times
+		return node, node.count
+	}
+
+	return balanceTree(node), count
+}
+
+// createTreeDelete returns the balanced tree without the node, which equals to target
+func createTreeDelete(node *treeNode, target Object, cb Comparator) *treeNode {
+	if node == nil {
+		return nil
+	}
+
+	if diff := cb(target, node.value); diff < 0 {
+		node.left = createTreeDelete(node.left, target, cb)
+	} else if diff > 0 {
+		node.right = createTreeDelete(node.right, target, cb)
+	} else {
+		if node.left == nil {
+			return node.right
+		}
+		if node.right == nil {
+			return node.left
+		}
+
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+
+		node.value, node.count = successor.value, successor.count
+		node.right = createTreeDelete(node.right, successor.value, cb)
+	}
+
+	return balanceTree(node)
+}
+
+// balanceTree returns the subtree, rotated to keep heights of its branches close
+func balanceTree(node *treeNode) *treeNode {
+	updateHeight(node)
+
+	if balance := treeHeight(node.left) - treeHeight(node.right); balance > 1 {
+		if treeHeight(node.left.left) < treeHeight(node.left.right) {
+			node.left = rotateLeft(node.left)
+		}
+		return rotateRight(node)
+	} else if balance < -1 {
+		if treeHeight(node.right.right) < treeHeight(node.right.left) {
+			node.right = rotateRight(node.right)
+		}
+		return rotateLeft(node)
+	}
+
+	return node
+}
+
+func rotateLeft(node *treeNode) *treeNode {
+	pivot := node.right
+	node.right = pivot.left
+	pivot.left = node
+	updateHeight(node)
+	updateHeight(pivot)
+	return pivot
+}
+
+func rotateRight(node *treeNode) *treeNode {
+	pivot := node.left
+	node.left = pivot.right
+	pivot.right = node
+	updateHeight(node)
+	updateHeight(pivot)
+	return pivot
+}
+
+func updateHeight(node *treeNode) {
+	left, right := treeHeight(node.left), treeHeight(node.right)
+	if left > right {
+		node.height = left + 1
+	} else {
+		node.height = right + 1
+	}
+}
+
+func treeHeight(node *treeNode) int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
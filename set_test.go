@@ -0,0 +1,268 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	type point struct{ x []int }
+	pointComparator := func(l, r Object) int { return l.(point).x[0] - r.(point).x[0] }
+
+	g.Describe("#NewSet()", func() {
+		g.It("Should create Set of unique elements", func() {
+			set := NewSet(Seq{4, 2, 4, 8, 2})
+
+			g.Assert(set.Len()).Equal(3)
+			g.Assert(EqualsNotStrict(set.Seq(), Seq{2, 4, 8}, intComparator)).IsTrue()
+			g.Assert(NewSet(nil).Len()).Equal(0)
+			g.Assert(NewSet(nil).Seq()).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#NewSetBy()", func() {
+		g.It("Should create sorted Set of values, which are not comparable", func() {
+			set := NewSetBy(Seq{point{x: []int{3}}, point{x: []int{1}}, point{x: []int{3}}}, pointComparator)
+
+			g.Assert(set.Len()).Equal(2)
+			g.Assert(set.Seq()[0].(point).x[0]).Equal(1)
+			g.Assert(set.Has(point{x: []int{3}})).IsTrue()
+			g.Assert(set.Has(point{x: []int{2}})).IsFalse()
+		})
+
+		g.It("Should keep elements sorted after many operations", func() {
+			set := NewSetBy(nil, intComparator)
+			for i := 0; i < 100; i++ {
+				set.Add((i * 37) % 100)
+			}
+			for i := 0; i < 100; i += 2 {
+				set.Remove(i)
+			}
+
+			g.Assert(set.Len()).Equal(50)
+			g.Assert(set.Seq()[0]).Equal(1)
+			g.Assert(set.Seq()[49]).Equal(99)
+			g.Assert(SortBy(set.Seq(), intComparator)).Equal(set.Seq())
+		})
+
+		g.It("Should fall back to hash-backed Set without Comparator", func() {
+			g.Assert(NewSetBy(Seq{1, 1, 2}, nil).Len()).Equal(2)
+		})
+	})
+
+	g.Describe("#Add() and #Remove()", func() {
+		g.It("Should report whether Set has changed", func() {
+			for _, set := range []*Set{NewSet(Seq{1}), NewSetBy(Seq{1}, intComparator)} {
+				g.Assert(set.Add(1)).IsFalse()
+				g.Assert(set.Add(2)).IsTrue()
+				g.Assert(set.Remove(1)).IsTrue()
+				g.Assert(set.Remove(1)).IsFalse()
+				g.Assert(set.Seq()).Equal(Seq{2})
+			}
+		})
+		g.It("Should work with the zero Set", func() {
+			var set Set
+
+			g.Assert(set.Has(1)).IsFalse()
+			g.Assert(set.Add(1)).IsTrue()
+			g.Assert(set.Add(1)).IsFalse()
+			g.Assert(set.Len()).Equal(1)
+			g.Assert(set.Union(NewSet(Seq{2})).Len()).Equal(2)
+		})
+	})
+
+	g.Describe("#Each()", func() {
+		g.It("Should call Action on each element in order", func() {
+			set := NewSetBy(Seq{3, 1, 2}, intComparator)
+			result := NewSeq(3)
+
+			set.Each(func(cur, index, _ Object) { result[index.(int)] = cur })
+			set.Each(nil)
+
+			g.Assert(result).Equal(Seq{1, 2, 3})
+		})
+	})
+
+	g.Describe("Set algebra", func() {
+		g.It("Should return union, intersection and differences of Sets", func() {
+			for _, create := range []func(Seq) *Set{NewSet, func(seq Seq) *Set { return NewSetBy(seq, intComparator) }} {
+				left := create(Seq{1, 2, 3, 4})
+				right := create(Seq{3, 4, 5})
+
+				g.Assert(EqualsNotStrict(left.Union(right).Seq(), Seq{1, 2, 3, 4, 5}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.Intersect(right).Seq(), Seq{3, 4}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.Difference(right).Seq(), Seq{1, 2}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.SymmetricDifference(right).Seq(), Seq{1, 2, 5}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.Union(nil).Seq(), Seq{1, 2, 3, 4}, intComparator)).IsTrue()
+				g.Assert(left.Intersect(nil).Len()).Equal(0)
+				g.Assert(left.Len()).Equal(4)
+				g.Assert(right.Len()).Equal(3)
+			}
+		})
+
+		g.It("Should mix hash-backed and tree-backed Sets", func() {
+			left := NewSetBy(Seq{5, 1, 3}, intComparator)
+			right := NewSet(Seq{2, 3})
+
+			g.Assert(left.Union(right).Seq()).Equal(Seq{1, 2, 3, 5})
+			g.Assert(left.SymmetricDifference(right).Seq()).Equal(Seq{1, 2, 5})
+		})
+
+		g.It("Should compare elements of mixed Sets the way the receiver does", func() {
+			points := NewSetBy(Seq{point{[]int{1}}, point{[]int{2}}}, pointComparator)
+			ints := NewSetBy(Seq{2, 3}, intComparator)
+
+			g.Assert(points.Intersect(&Set{}).Len()).Equal(0)
+			g.Assert(points.Difference(NewSet(nil)).Len()).Equal(2)
+			g.Assert(points.SymmetricDifference(&Set{}).Len()).Equal(2)
+			g.Assert(points.IsSubset(NewSet(nil))).IsFalse()
+			g.Assert(points.Union(nil).Len()).Equal(2)
+
+			g.Assert(NewSet(Seq{1, 2}).Intersect(ints).Seq()).Equal(Seq{2})
+			g.Assert(EqualsNotStrict(NewSet(Seq{1, 2}).Union(ints).Seq(), Seq{1, 2, 3}, intComparator)).IsTrue()
+			g.Assert(NewSet(Seq{2}).IsSubset(ints)).IsTrue()
+		})
+
+		g.It("Should panic when non-comparable elements go into hash-backed Set", func() {
+			defer func() {
+				g.Assert(recover()).Equal("ugo: can't put non-comparable ugo_test.point into the hash-backed set")
+			}()
+
+			NewSet(Seq{1}).Union(NewSetBy(Seq{point{[]int{1}}}, pointComparator))
+		})
+
+		g.It("Should check whether Set is a subset of another one", func() {
+			g.Assert(NewSet(Seq{1, 2}).IsSubset(NewSet(Seq{1, 2, 3}))).IsTrue()
+			g.Assert(NewSet(Seq{1, 4}).IsSubset(NewSet(Seq{1, 2, 3}))).IsFalse()
+			g.Assert(NewSet(nil).IsSubset(NewSet(Seq{1}))).IsTrue()
+			g.Assert(NewSet(Seq{1}).IsSubset(nil)).IsFalse()
+		})
+	})
+}
+
+func TestMultiSet(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	creators := []func(Seq) *MultiSet{NewMultiSet, func(seq Seq) *MultiSet { return NewMultiSetBy(seq, intComparator) }}
+
+	g.Describe("#NewMultiSet() and #NewMultiSetBy()", func() {
+		g.It("Should count occurrences of elements", func() {
+			for _, create := range creators {
+				multi := create(Seq{4, 2, 4, 8, 4})
+
+				g.Assert(multi.Len()).Equal(5)
+				g.Assert(multi.Distinct()).Equal(3)
+				g.Assert(multi.Count(4)).Equal(3)
+				g.Assert(multi.Count(9)).Equal(0)
+				g.Assert(multi.Has(2)).IsTrue()
+				g.Assert(EqualsNotStrict(multi.Seq(), Seq{4, 2, 4, 8, 4}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(multi.Set().Seq(), Seq{2, 4, 8}, intComparator)).IsTrue()
+			}
+
+			g.Assert(NewMultiSetBy(Seq{3, 1, 3}, intComparator).Seq()).Equal(Seq{1, 3, 3})
+			g.Assert(NewMultiSetBy(Seq{3, 1, 3}, nil).Len()).Equal(3)
+		})
+	})
+
+	g.Describe("#Add() and #Remove()", func() {
+		g.It("Should change count of element", func() {
+			for _, create := range creators {
+				multi := create(nil)
+
+				g.Assert(multi.Add(1)).Equal(1)
+				g.Assert(multi.Add(1)).Equal(2)
+				g.Assert(multi.Remove(1)).IsTrue()
+				g.Assert(multi.Count(1)).Equal(1)
+				g.Assert(multi.Remove(1)).IsTrue()
+				g.Assert(multi.Remove(1)).IsFalse()
+				g.Assert(multi.Has(1)).IsFalse()
+				g.Assert(multi.Len()).Equal(0)
+				g.Assert(multi.Distinct()).Equal(0)
+			}
+		})
+		g.It("Should work with the zero MultiSet", func() {
+			var multi MultiSet
+
+			g.Assert(multi.Count(1)).Equal(0)
+			g.Assert(multi.Add(1)).Equal(1)
+			g.Assert(multi.Add(1)).Equal(2)
+			g.Assert(multi.Len()).Equal(2)
+		})
+	})
+
+	g.Describe("#Each()", func() {
+		g.It("Should call Action on each unique element with its count", func() {
+			counts := make(map[int]int)
+
+			NewMultiSetBy(Seq{1, 2, 2}, intComparator).Each(func(cur, count, _ Object) { counts[cur.(int)] = count.(int) })
+			NewMultiSet(Seq{1}).Each(nil)
+
+			g.Assert(counts).Equal(map[int]int{1: 1, 2: 2})
+		})
+	})
+
+	g.Describe("MultiSet algebra", func() {
+		g.It("Should combine counts of elements", func() {
+			for _, create := range creators {
+				left := create(Seq{1, 1, 1, 2, 3})
+				right := create(Seq{1, 2, 2, 4})
+
+				g.Assert(EqualsNotStrict(left.Union(right).Seq(), Seq{1, 1, 1, 2, 2, 3, 4}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.Intersect(right).Seq(), Seq{1, 2}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.Difference(right).Seq(), Seq{1, 1, 3}, intComparator)).IsTrue()
+				g.Assert(EqualsNotStrict(left.SymmetricDifference(right).Seq(), Seq{1, 1, 2, 3, 4}, intComparator)).IsTrue()
+				g.Assert(left.Union(right).Len()).Equal(7)
+				g.Assert(left.Difference(nil).Len()).Equal(5)
+				g.Assert(left.Len()).Equal(5)
+			}
+		})
+
+		g.It("Should check whether MultiSet is a subset of another one", func() {
+			g.Assert(NewMultiSet(Seq{1, 1}).IsSubset(NewMultiSet(Seq{1, 1, 2}))).IsTrue()
+			g.Assert(NewMultiSet(Seq{1, 1}).IsSubset(NewMultiSet(Seq{1, 2}))).IsFalse()
+			g.Assert(NewMultiSet(Seq{1}).IsSubset(nil)).IsFalse()
+		})
+
+		g.It("Should combine with nil and mixed MultiSets", func() {
+			slices := NewMultiSetBy(Seq{[]int{1}, []int{1}, []int{2}}, func(l, r Object) int { return l.([]int)[0] - r.([]int)[0] })
+
+			g.Assert(slices.Union(nil).Len()).Equal(3)
+			g.Assert(slices.Intersect(nil).Len()).Equal(0)
+			g.Assert(slices.IsSubset(nil)).IsFalse()
+
+			left := NewMultiSetBy(Seq{1, 1, 2}, intComparator)
+			right := NewMultiSet(Seq{1, 3})
+
+			g.Assert(left.Union(right).Seq()).Equal(Seq{1, 1, 2, 3})
+			g.Assert(left.Difference(right).Seq()).Equal(Seq{1, 2})
+			g.Assert(EqualsNotStrict(right.Intersect(left).Seq(), Seq{1}, intComparator)).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,176 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// SortedSeq is the special struct, which keeps its elements
+// ordered by the Comparator it was created with,
+// the zero SortedSeq is the empty one, which orders elements by #Compare
+type SortedSeq struct {
+	seq Seq        // seq is the ordered storage
+	cb  Comparator // cb is the Comparator, which defines the order
+}
+
+// NewSortedSeq creates a new SortedSeq from the copy of given slice,
+// returns nil if Comparator is not passed
+func NewSortedSeq(seq Seq, cb Comparator) *SortedSeq {
+	if cb == nil {
+		return nil
+	}
+
+	copied := NewSeq(len(seq))
+	copy(copied, seq)
+
+	return &SortedSeq{seq: SortBy(copied, cb), cb: cb}
+}
+
+// Len returns the count of elements in SortedSeq
+func (sorted *SortedSeq) Len() int {
+	return len(sorted.seq)
+}
+
+// At returns the element on given position, or nil if position is out of bounds
+func (sorted *SortedSeq) At(position int) Object {
+	if position < 0 || position >= len(sorted.seq) {
+		return nil
+	}
+	return sorted.seq[position]
+}
+
+// Seq returns the copy of ordered elements
+func (sorted *SortedSeq) Seq() Seq {
+	copied := NewSeq(len(sorted.seq))
+	copy(copied, sorted.seq)
+	return copied
+}
+
+// Add pushes an element into its sorted position and returns that position,
+// equal elements keep the order they were added in
+func (sorted *SortedSeq) Add(target Object) int {
+	position := sorted.UpperBound(target)
+	if sorted.seq == nil {
+		sorted.seq = NewSeq(0)
+	}
+	sorted.seq = Insert(sorted.seq, target, position)
+	return position
+}
+
+// Remove takes the first element, which equals to passed one(target),
+// returns false if there is no such element
+func (sorted *SortedSeq) Remove(target Object) bool {
+	position := sorted.LowerBound(target)
+
+	if position == len(sorted.seq) || sorted.comparator()(sorted.seq[position], target) != 0 {
+		return false
+	}
+
+	copy(sorted.seq[position:], sorted.seq[position+1:])
+	sorted.seq[len(sorted.seq)-1] = nil
+	sorted.seq = sorted.seq[:len(sorted.seq)-1]
+
+	return true
+}
+
+// SortedIndex returns the lowest position at which target
+// should be inserted to keep the order, just like underscore's sortedIndex
+func (sorted *SortedSeq) SortedIndex(target Object) int {
+	return sorted.LowerBound(target)
+}
+
+// LowerBound returns the position of the first element, which is not less than target
+func (sorted *SortedSeq) LowerBound(target Object) int {
+	return createLowerBound(sorted.seq, target, sorted.comparator(), len(sorted.seq))
+}
+
+// UpperBound returns the position of the first element, which is larger than target
+func (sorted *SortedSeq) UpperBound(target Object) int {
+	return createUpperBound(sorted.seq, target, sorted.comparator(), len(sorted.seq))
+}
+
+// RangeBetween returns the elements, which are not less than lo and not larger than hi
+func (sorted *SortedSeq) RangeBetween(lo, hi Object) Seq {
+	from := sorted.LowerBound(lo)
+	to := sorted.UpperBound(hi)
+
+	if from >= to {
+		return Seq{}
+	}
+
+	result := NewSeq(to - from)
+	copy(result, sorted.seq[from:to])
+
+	return result
+}
+
+// Merge returns a new SortedSeq, which contains elements of both SortedSeq's,
+// ordered by the Comparator of the receiver
+func (sorted *SortedSeq) Merge(other *SortedSeq) *SortedSeq {
+	cb := sorted.comparator()
+
+	if other == nil {
+		return NewSortedSeq(sorted.seq, cb)
+	}
+	if !isSortedBy(other.seq, cb) {
+		return NewSortedSeq(Concat(sorted.Seq(), other.seq), cb)
+	}
+
+	return &SortedSeq{seq: createMerge(sorted.seq, other.seq, cb), cb: cb}
+}
+
+/* private methods */
+// comparator returns the Comparator of SortedSeq, setting #Compare for the zero SortedSeq
+func (sorted *SortedSeq) comparator() Comparator {
+	if sorted.cb == nil {
+		sorted.cb = Compare
+	}
+	return sorted.cb
+}
+
+// createMerge returns the new slice, which contains elements of both sorted slices,
+// elements of the left one go first if they're equal
+func createMerge(left, right Seq, cb Comparator) Seq {
+	result := make(Seq, 0, len(left)+len(right))
+	i, j := 0, 0
+
+	for i < len(left) && j < len(right) {
+		if cb(right[j], left[i]) < 0 {
+			result = append(result, right[j])
+			j++
+		} else {
+			result = append(result, left[i])
+			i++
+		}
+	}
+
+	result = append(result, left[i:]...)
+	return append(result, right[j:]...)
+}
+
+// isSortedBy returns true if every element is not less than the previous one
+func isSortedBy(seq Seq, cb Comparator) bool {
+	for i := 1; i < len(seq); i++ {
+		if cb(seq[i], seq[i-1]) < 0 {
+			return false
+		}
+	}
+	return true
+}
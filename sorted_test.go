@@ -0,0 +1,156 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"testing"
+)
+
+func TestSortedSeq(t *testing.T) {
+	g := Goblin(t)
+
+	intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+	tensComparator := func(l, r Object) int { return l.(int)/10 - r.(int)/10 }
+
+	g.Describe("#NewSortedSeq()", func() {
+		g.It("Should return sorted copy of given Seq", func() {
+			inSeq := Seq{17, 2, 8, 6, 2, 4, 10, 7, 10, 120}
+
+			g.Assert(NewSortedSeq(inSeq, intComparator).Seq()).Equal(Seq{2, 2, 4, 6, 7, 8, 10, 10, 17, 120})
+			g.Assert(inSeq).Equal(Seq{17, 2, 8, 6, 2, 4, 10, 7, 10, 120})
+			g.Assert(NewSortedSeq(nil, intComparator).Seq()).Equal(Seq{})
+			g.Assert(NewSortedSeq(nil, intComparator).Len()).Equal(0)
+			g.Assert(NewSortedSeq(inSeq, nil) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("#At()", func() {
+		g.It("Should return element on given position or nil", func() {
+			sorted := NewSortedSeq(Seq{3, 1, 2}, intComparator)
+
+			g.Assert(sorted.At(0)).Equal(1)
+			g.Assert(sorted.At(2)).Equal(3)
+			g.Assert(sorted.At(3)).Equal(nil)
+			g.Assert(sorted.At(-1)).Equal(nil)
+		})
+	})
+
+	g.Describe("#Add()", func() {
+		g.It("Should insert element keeping the order", func() {
+			sorted := NewSortedSeq(Seq{10, 2, 6}, intComparator)
+
+			g.Assert(sorted.Add(7)).Equal(2)
+			g.Assert(sorted.Add(1)).Equal(0)
+			g.Assert(sorted.Add(11)).Equal(5)
+			g.Assert(sorted.Add(6)).Equal(3)
+			g.Assert(sorted.Seq()).Equal(Seq{1, 2, 6, 6, 7, 10, 11})
+		})
+
+		g.It("Should keep equal elements in the order they were added", func() {
+			sorted := NewSortedSeq(Seq{}, tensComparator)
+
+			sorted.Add(15)
+			sorted.Add(3)
+			sorted.Add(11)
+			sorted.Add(19)
+
+			g.Assert(sorted.Seq()).Equal(Seq{3, 15, 11, 19})
+		})
+
+		g.It("Should work with the zero SortedSeq", func() {
+			sorted := &SortedSeq{}
+
+			g.Assert(sorted.Remove(1)).IsFalse()
+			g.Assert(sorted.Add(5)).Equal(0)
+			g.Assert(sorted.Add("a")).Equal(1)
+			g.Assert(sorted.Add(2)).Equal(0)
+			g.Assert(sorted.Seq()).Equal(Seq{2, 5, "a"})
+			g.Assert((&SortedSeq{}).Merge(sorted).Seq()).Equal(Seq{2, 5, "a"})
+		})
+	})
+
+	g.Describe("#Remove()", func() {
+		g.It("Should remove the first equal element", func() {
+			sorted := NewSortedSeq(Seq{4, 2, 2, 8}, intComparator)
+
+			g.Assert(sorted.Remove(2)).IsTrue()
+			g.Assert(sorted.Seq()).Equal(Seq{2, 4, 8})
+			g.Assert(sorted.Remove(8)).IsTrue()
+			g.Assert(sorted.Seq()).Equal(Seq{2, 4})
+			g.Assert(sorted.Remove(5)).IsFalse()
+			g.Assert(sorted.Remove(99)).IsFalse()
+			g.Assert(sorted.Len()).Equal(2)
+		})
+	})
+
+	g.Describe("#SortedIndex()", func() {
+		g.It("Should return the lowest insertion position", func() {
+			sorted := NewSortedSeq(Seq{10, 20, 30, 30, 40}, intComparator)
+
+			g.Assert(sorted.SortedIndex(35)).Equal(4)
+			g.Assert(sorted.SortedIndex(30)).Equal(2)
+			g.Assert(sorted.SortedIndex(0)).Equal(0)
+			g.Assert(sorted.SortedIndex(50)).Equal(5)
+		})
+	})
+
+	g.Describe("#LowerBound() and #UpperBound()", func() {
+		g.It("Should return bounds of equal elements", func() {
+			sorted := NewSortedSeq(Seq{1, 3, 3, 3, 5}, intComparator)
+
+			g.Assert(sorted.LowerBound(3)).Equal(1)
+			g.Assert(sorted.UpperBound(3)).Equal(4)
+			g.Assert(sorted.LowerBound(4)).Equal(4)
+			g.Assert(sorted.UpperBound(4)).Equal(4)
+			g.Assert(sorted.LowerBound(0)).Equal(0)
+			g.Assert(sorted.UpperBound(9)).Equal(5)
+		})
+	})
+
+	g.Describe("#RangeBetween()", func() {
+		g.It("Should return elements between lo and hi inclusively", func() {
+			sorted := NewSortedSeq(Seq{9, 1, 5, 3, 7, 5}, intComparator)
+
+			g.Assert(sorted.RangeBetween(3, 7)).Equal(Seq{3, 5, 5, 7})
+			g.Assert(sorted.RangeBetween(4, 6)).Equal(Seq{5, 5})
+			g.Assert(sorted.RangeBetween(10, 20)).Equal(Seq{})
+			g.Assert(sorted.RangeBetween(7, 3)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Merge()", func() {
+		g.It("Should return merged SortedSeq", func() {
+			left := NewSortedSeq(Seq{5, 1, 3}, intComparator)
+			right := NewSortedSeq(Seq{4, 2, 6, 1}, intComparator)
+			reversed := NewSortedSeq(Seq{4, 2, 6}, func(l, r Object) int { return r.(int) - l.(int) })
+
+			g.Assert(left.Merge(right).Seq()).Equal(Seq{1, 1, 2, 3, 4, 5, 6})
+			g.Assert(left.Merge(reversed).Seq()).Equal(Seq{1, 2, 3, 4, 5, 6})
+			g.Assert(left.Merge(nil).Seq()).Equal(Seq{1, 3, 5})
+			g.Assert(left.Seq()).Equal(Seq{1, 3, 5})
+			g.Assert(right.Seq()).Equal(Seq{1, 2, 4, 6})
+		})
+	})
+}
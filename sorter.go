@@ -0,0 +1,309 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"math"
+	"slices"
+
+	sorter "github.com/alxrm/ugo/timsort"
+)
+
+// Sorter is the sorting algorithm, which orders the slice in place by the Comparator
+type Sorter interface {
+	Sort(seq Seq, cb Comparator)
+}
+
+// TimSort is the Sorter, which uses stable timsort algorithm, it's the default one (see #SortBy)
+type TimSort struct{}
+
+// PdqSort is the Sorter, which uses pattern-defeating quicksort of the standard library,
+// it's usually faster than TimSort, but it's not stable
+type PdqSort struct{}
+
+// RadixSort is the Sorter, which orders the values by their integer or string keys in linear time,
+// if some of the keys have another type, it falls back to the stable sort of keys by #Compare,
+// the order is always stable and ascending by keys, so it ignores the Comparator
+type RadixSort struct {
+	Key Callback // Key returns the key of value, if it's not passed, the values themselves are the keys
+}
+
+// PartialSort is the Sorter, which orders only K least values (see #SortFirstK)
+type PartialSort struct {
+	K int
+}
+
+// Sort sorts the slice in place by the Comparator, does nothing if Comparator is not passed
+func (TimSort) Sort(seq Seq, cb Comparator) {
+	if cb != nil {
+		sorter.Sort(seq, lessThan(cb))
+	}
+}
+
+// Sort sorts the slice in place by the Comparator, does nothing if Comparator is not passed
+func (PdqSort) Sort(seq Seq, cb Comparator) {
+	if cb != nil {
+		slices.SortFunc(seq, func(left, right interface{}) int { return cb(left, right) })
+	}
+}
+
+// Sort sorts the slice in place ascending by the keys, the Comparator is ignored
+func (radix RadixSort) Sort(seq Seq, _ Comparator) {
+	items, isText, ok := createRadixItems(seq, radix.Key)
+
+	switch {
+	case !ok:
+		sortRadixKeys(seq, radix.Key)
+		return
+	case isText:
+		sortRadixText(items, make([]radixItem, len(items)), 0)
+	default:
+		sortRadixNumbers(items)
+	}
+
+	for index, item := range items {
+		seq[index] = item.value
+	}
+}
+
+// Sort sorts K least values of the slice in place (see #SortFirstK)
+func (partial PartialSort) Sort(seq Seq, cb Comparator) {
+	SortFirstK(seq, partial.K, cb)
+}
+
+// SortWith sorts the slice in place by given Sorter, if Sorter is not passed, it uses TimSort
+// NOTE: it sorts given slice in place, copy it to keep it untouched
+func SortWith(seq Seq, algo Sorter, cb Comparator) Seq {
+	if seq == nil {
+		return Seq{}
+	}
+	if algo == nil {
+		algo = TimSort{}
+	}
+
+	algo.Sort(seq, cb)
+	return seq
+}
+
+// SortFirstK moves k least values of the slice to its start in sorted order, the rest of the values follow them
+// in no particular order, it takes O(n log k) operations, so it's faster than sorting for the small k
+// NOTE: it sorts given slice in place
+func SortFirstK(seq Seq, k int, cb Comparator) Seq {
+	if seq == nil {
+		return Seq{}
+	}
+	if cb == nil || k <= 0 {
+		return seq
+	}
+	if k > len(seq) {
+		k = len(seq)
+	}
+
+	heap := &Heap{seq: seq[:k], cb: func(l, r Object) int { return cb(r, l) }}
+	for index := k/2 - 1; index >= 0; index-- {
+		heap.down(index)
+	}
+
+	for index := k; index < len(seq); index++ {
+		if cb(seq[index], seq[0]) < 0 {
+			seq[0], seq[index] = seq[index], seq[0]
+			heap.down(0)
+		}
+	}
+
+	for end := k - 1; end > 0; end-- {
+		seq[0], seq[end] = seq[end], seq[0]
+		heap.seq = seq[:end]
+		heap.down(0)
+	}
+
+	return seq
+}
+
+/* private methods */
+// radixItem is the value with its key, which is either the number or the text
+type radixItem struct {
+	number uint64
+	text   string
+	value  Object
+}
+
+// radixInsertion is the length of range, which is sorted by insertion instead of the next radix pass
+const radixInsertion = 16
+
+// createRadixItems returns the keys of values, the integer keys are shifted to keep their order as unsigned,
+// the last value is false if keys are neither all integers nor all strings
+func createRadixItems(seq Seq, key Callback) ([]radixItem, bool, bool) {
+	items := make([]radixItem, len(seq))
+	isText := false
+
+	for index, value := range seq {
+		current := createKey(value, index, seq, key)
+		items[index].value = value
+
+		if text, ok := current.(string); ok {
+			if index > 0 && !isText {
+				return nil, false, false
+			}
+			items[index].text, isText = text, true
+			continue
+		}
+
+		number, ok := createRadixNumber(current)
+		if !ok || isText {
+			return nil, false, false
+		}
+		items[index].number = number
+	}
+
+	return items, isText, true
+}
+
+// sortRadixKeys sorts the values stably by #Compare of their keys, when they're neither all integers nor all strings
+func sortRadixKeys(seq Seq, key Callback) {
+	pairs := make([][2]Object, len(seq))
+	for index, value := range seq {
+		pairs[index] = [2]Object{createKey(value, index, seq, key), value}
+	}
+
+	slices.SortStableFunc(pairs, func(left, right [2]Object) int { return Compare(left[0], right[0]) })
+
+	for index, pair := range pairs {
+		seq[index] = pair[1]
+	}
+}
+
+func createRadixNumber(value Object) (uint64, bool) {
+	var signed int64
+
+	switch number := value.(type) {
+	case int:
+		signed = int64(number)
+	case int8:
+		signed = int64(number)
+	case int16:
+		signed = int64(number)
+	case int32:
+		signed = int64(number)
+	case int64:
+		signed = number
+	case uint:
+		if uint64(number) > math.MaxInt64 {
+			return 0, false
+		}
+		signed = int64(number)
+	case uint8:
+		signed = int64(number)
+	case uint16:
+		signed = int64(number)
+	case uint32:
+		signed = int64(number)
+	case uint64:
+		if number > math.MaxInt64 {
+			return 0, false
+		}
+		signed = int64(number)
+	default:
+		return 0, false
+	}
+
+	return uint64(signed) ^ 1<<63, true
+}
+
+// sortRadixNumbers sorts by the bytes of keys from the least significant one, skipping the bytes, which are the same
+func sortRadixNumbers(items []radixItem) {
+	if len(items) < 2 {
+		return
+	}
+
+	source, buffer := items, make([]radixItem, len(items))
+
+	for shift := uint(0); shift < 64; shift += 8 {
+		var counts [257]int
+		for _, item := range source {
+			counts[(item.number>>shift)&0xff+1]++
+		}
+		if counts[(source[0].number>>shift)&0xff+1] == len(items) {
+			continue
+		}
+
+		for index := 1; index < len(counts); index++ {
+			counts[index] += counts[index-1]
+		}
+		for _, item := range source {
+			digit := (item.number >> shift) & 0xff
+			buffer[counts[digit]] = item
+			counts[digit]++
+		}
+
+		source, buffer = buffer, source
+	}
+
+	copy(items, source)
+}
+
+// sortRadixText sorts by the bytes of keys from the most significant one, the keys, which end at depth, go first
+func sortRadixText(items, buffer []radixItem, depth int) {
+	if len(items) <= radixInsertion {
+		for index := 1; index < len(items); index++ {
+			for position := index; position > 0 && items[position].text[depth:] < items[position-1].text[depth:]; position-- {
+				items[position], items[position-1] = items[position-1], items[position]
+			}
+		}
+		return
+	}
+
+	var counts [258]int
+	for _, item := range items {
+		counts[createRadixDigit(item.text, depth)+1]++
+	}
+	if counts[1] == len(items) {
+		return
+	}
+
+	for index := 1; index < len(counts); index++ {
+		counts[index] += counts[index-1]
+	}
+
+	starts := counts
+	for _, item := range items {
+		digit := createRadixDigit(item.text, depth)
+		buffer[counts[digit]] = item
+		counts[digit]++
+	}
+	copy(items, buffer[:len(items)])
+
+	for digit := 1; digit < 257; digit++ {
+		if start, end := starts[digit], starts[digit+1]; end-start > 1 {
+			sortRadixText(items[start:end], buffer[start:end], depth+1)
+		}
+	}
+}
+
+// createRadixDigit returns 0 for the key, which ends before depth, and the byte + 1 otherwise
+func createRadixDigit(text string, depth int) int {
+	if depth >= len(text) {
+		return 0
+	}
+	return int(text[depth]) + 1
+}
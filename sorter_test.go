@@ -0,0 +1,185 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"fmt"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math/rand"
+	"testing"
+)
+
+func TestSorter(t *testing.T) {
+	g := Goblin(t)
+
+	numbers := func() Seq { return Seq{5, -3, 8, 0, 300, -70000, 8, 1 << 40, 2} }
+	sortedNumbers := Seq{-70000, -3, 0, 2, 5, 8, 8, 300, 1 << 40}
+
+	g.Describe("#SortWith()", func() {
+		g.It("Should sort by every Sorter", func() {
+			for _, algo := range []Sorter{nil, TimSort{}, PdqSort{}, RadixSort{}, PartialSort{K: 9}} {
+				g.Assert(SortWith(numbers(), algo, Compare)).Equal(sortedNumbers)
+			}
+		})
+
+		g.It("Should keep the order of equal keys by TimSort and RadixSort", func() {
+			records := func() Seq {
+				return Seq{Seq{2, "a"}, Seq{1, "b"}, Seq{2, "c"}, Seq{1, "d"}}
+			}
+			byFirst := func(l, r Object) int { return Compare(l.(Seq)[0], r.(Seq)[0]) }
+			first := func(cur, _, _ Object) Object { return cur.(Seq)[0] }
+			expected := Seq{Seq{1, "b"}, Seq{1, "d"}, Seq{2, "a"}, Seq{2, "c"}}
+
+			g.Assert(SortWith(records(), TimSort{}, byFirst)).Equal(expected)
+			g.Assert(SortWith(records(), RadixSort{Key: first}, nil)).Equal(expected)
+		})
+
+		g.It("Should handle empty and nil slices", func() {
+			g.Assert(SortWith(nil, PdqSort{}, Compare)).Equal(Seq{})
+			g.Assert(SortWith(Seq{}, RadixSort{}, Compare)).Equal(Seq{})
+			g.Assert(SortWith(Seq{3, 1}, PdqSort{}, nil)).Equal(Seq{3, 1})
+		})
+	})
+
+	g.Describe("#RadixSort", func() {
+		g.It("Should sort strings by bytes", func() {
+			words := Seq{"banana", "", "apple", "b", "ban", "bandana", "apple", "Zebra", "a"}
+			for index := 0; index < 40; index++ {
+				words = append(words, fmt.Sprintf("word%02d", 39-index))
+			}
+
+			expected := SortedCopy(words, Compare)
+			g.Assert(SortWith(words, RadixSort{}, nil)).Equal(expected)
+		})
+
+		g.It("Should sort many random integers", func() {
+			rng := rand.New(rand.NewSource(1))
+			values := NewSeq(1000)
+			for index := range values {
+				values[index] = rng.Int63n(1<<50) - 1<<49
+			}
+
+			expected := SortedCopy(values, Compare)
+			g.Assert(SortWith(values, RadixSort{}, nil)).Equal(expected)
+		})
+
+		g.It("Should fall back to the stable sort by Compare for other keys", func() {
+			g.Assert(SortWith(Seq{2.5, 1, "a"}, RadixSort{}, nil)).Equal(Seq{1, 2.5, "a"})
+			g.Assert(SortWith(Seq{"b", 1}, RadixSort{}, nil)).Equal(Seq{1, "b"})
+			g.Assert(SortWith(Seq{uint64(1 << 63), uint64(1)}, RadixSort{}, nil)).Equal(Seq{uint64(1), uint64(1 << 63)})
+
+			half := func(cur, _, _ Object) Object { return float64(cur.(int)) / 2 }
+			g.Assert(SortWith(Seq{3, -2, 4, 1}, RadixSort{Key: half}, nil)).Equal(Seq{-2, 1, 3, 4})
+		})
+
+		g.It("Should sort ascending by keys whatever the Comparator is", func() {
+			descending := NegateComparator(Compare)
+
+			g.Assert(SortWith(Seq{2, 3, 1}, RadixSort{}, descending)).Equal(Seq{1, 2, 3})
+			g.Assert(SortWith(Seq{"b", "c", "a"}, RadixSort{}, descending)).Equal(Seq{"a", "b", "c"})
+			g.Assert(SortWith(Seq{2.5, 3, 1}, RadixSort{}, descending)).Equal(Seq{1, 2.5, 3})
+		})
+	})
+
+	g.Describe("#SortFirstK()", func() {
+		g.It("Should move k least values to the start in order", func() {
+			res := SortFirstK(numbers(), 3, Compare)
+
+			g.Assert(res[:3]).Equal(Seq{-70000, -3, 0})
+			g.Assert(EqualsNotStrict(res, numbers(), Compare)).IsTrue()
+		})
+
+		g.It("Should handle the edge cases of k", func() {
+			g.Assert(SortFirstK(numbers(), 0, Compare)).Equal(numbers())
+			g.Assert(SortFirstK(numbers(), 20, Compare)).Equal(sortedNumbers)
+			g.Assert(SortFirstK(numbers(), 2, nil)).Equal(numbers())
+			g.Assert(SortFirstK(nil, 2, Compare)).Equal(Seq{})
+		})
+	})
+
+	g.Describe("#Chain().SortWith()", func() {
+		g.It("Should sort the chained Seq by given Sorter", func() {
+			seq := numbers()
+
+			g.Assert(Chain(seq).SortWith(PdqSort{}, Compare).Value()).Equal(sortedNumbers)
+			g.Assert(Chain(Seq{3, 1, 2}).SortWith(PartialSort{K: 1}, Compare).First(1).Value()).Equal(Seq{1})
+		})
+	})
+}
+
+func BenchmarkSorters(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	length := 10000
+
+	random, nearlySorted, words, records := NewSeq(length), NewSeq(length), NewSeq(length), NewSeq(length)
+	for index := 0; index < length; index++ {
+		random[index] = rng.Intn(length * 10)
+		nearlySorted[index] = index
+		words[index] = fmt.Sprintf("user-%08d", rng.Intn(length*10))
+		records[index] = map[string]Object{"id": index, "age": rng.Intn(100)}
+	}
+	for index := 0; index < length/100; index++ {
+		left, right := rng.Intn(length), rng.Intn(length)
+		nearlySorted[left], nearlySorted[right] = nearlySorted[right], nearlySorted[left]
+	}
+
+	byAge := func(l, r Object) int {
+		return Compare(l.(map[string]Object)["age"], r.(map[string]Object)["age"])
+	}
+	age := func(cur, _, _ Object) Object { return cur.(map[string]Object)["age"] }
+
+	shapes := []struct {
+		name string
+		seq  Seq
+		cb   Comparator
+		key  Callback
+	}{
+		{"random", random, Compare, nil},
+		{"nearly sorted", nearlySorted, Compare, nil},
+		{"words", words, Compare, nil},
+		{"records", records, byAge, age},
+	}
+
+	for _, shape := range shapes {
+		algos := []struct {
+			name string
+			algo Sorter
+		}{
+			{"timsort", TimSort{}},
+			{"pdqsort", PdqSort{}},
+			{"radix", RadixSort{Key: shape.key}},
+			{"first 10", PartialSort{K: 10}},
+		}
+
+		for _, algo := range algos {
+			b.Run(shape.name+"/"+algo.name, func(b *testing.B) {
+				seq := NewSeq(len(shape.seq))
+				for index := 0; index < b.N; index++ {
+					copy(seq, shape.seq)
+					SortWith(seq, algo.algo, shape.cb)
+				}
+			})
+		}
+	}
+}
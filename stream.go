@@ -0,0 +1,486 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Stream is the lazy sequence, which produces its elements one by one, so it's never materialized
+// unless it's asked to, all of the callbacks receive the index of element in their source Stream as currentKey and nil as src
+type Stream struct {
+	next  func() (Object, error)
+	index int
+	err   error
+}
+
+// CSVOptions defines the way CSV rows are read and written
+type CSVOptions struct {
+	Header  bool     // Header makes the first line a header, so rows are map[string]Object keyed by its columns
+	Columns []string // Columns replaces the names of the header and defines the order of written map rows, by default keys of the first row are sorted
+	Comma   rune     // Comma is the field delimiter, ',' by default
+}
+
+// NewStream creates a new Stream over the elements of Seq
+func NewStream(seq Seq) *Stream {
+	index := 0
+
+	return &Stream{next: func() (Object, error) {
+		if index >= len(seq) {
+			return nil, io.EOF
+		}
+
+		index++
+		return seq[index-1], nil
+	}}
+}
+
+// StreamChan creates a new Stream, which receives elements from channel until it's closed
+func StreamChan(ch <-chan Object) *Stream {
+	return &Stream{next: func() (Object, error) {
+		if current, ok := <-ch; ok {
+			return current, nil
+		}
+		return nil, io.EOF
+	}}
+}
+
+// StreamJSONL creates a new Stream, which decodes one JSON value per line of newline-delimited JSON,
+// blank lines are skipped, in typed mode every line is expected to be the typed element like {"$type": "int", "$value": 1}
+func StreamJSONL(reader io.Reader, opts JSONOptions) *Stream {
+	buffered := bufio.NewReader(reader)
+	line := 0
+
+	return &Stream{next: func() (Object, error) {
+		for {
+			data, err := buffered.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+
+			line++
+			data = bytes.TrimSpace(data)
+
+			if len(data) == 0 {
+				if err == io.EOF {
+					return nil, io.EOF
+				}
+				continue
+			}
+
+			value, decodeErr := createJSONLValue(data, opts)
+			if decodeErr != nil {
+				return nil, fmt.Errorf("ugo: line %d: %v", line, decodeErr)
+			}
+
+			return value, nil
+		}
+	}}
+}
+
+// StreamCSV creates a new Stream, which reads rows of CSV as Seq of strings
+// or as map[string]Object keyed by the header, if CSVOptions#Header is set,
+// CSVOptions#Columns replace the names of the header line, and key the rows of CSV without header
+func StreamCSV(reader io.Reader, opts CSVOptions) *Stream {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		records.Comma = opts.Comma
+	}
+
+	header := opts.Columns
+	readHeader := opts.Header
+
+	return &Stream{next: func() (Object, error) {
+		record, err := records.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		if readHeader {
+			if readHeader = false; header == nil {
+				header = record
+			}
+
+			if record, err = records.Read(); err != nil {
+				return nil, err
+			}
+		}
+
+		if header == nil {
+			return createStringSeq(record), nil
+		}
+
+		if len(record) != len(header) {
+			line, _ := records.FieldPos(0)
+			return nil, fmt.Errorf("ugo: line %d: expected %d fields, got %d", line, len(header), len(record))
+		}
+
+		row := make(map[string]Object, len(header))
+		for index, column := range header {
+			row[column] = record[index]
+		}
+
+		return row, nil
+	}}
+}
+
+// ReadJSONL reads all of the values from newline-delimited JSON into Seq
+func ReadJSONL(reader io.Reader, opts JSONOptions) (Seq, error) {
+	return StreamJSONL(reader, opts).Seq()
+}
+
+// WriteJSONL writes every element of Seq as a separate line of JSON
+func WriteJSONL(writer io.Writer, seq Seq, opts JSONOptions) error {
+	return NewStream(seq).WriteJSONL(writer, opts)
+}
+
+// ReadCSV reads all of the rows from CSV into Seq
+func ReadCSV(reader io.Reader, opts CSVOptions) (Seq, error) {
+	return StreamCSV(reader, opts).Seq()
+}
+
+// WriteCSV writes every element of Seq as the CSV row, rows might be Seq, []string or map[string]Object
+func WriteCSV(writer io.Writer, seq Seq, opts CSVOptions) error {
+	return NewStream(seq).WriteCSV(writer, opts)
+}
+
+// Next returns the next element of Stream, it returns false when Stream is over or has failed
+func (stream *Stream) Next() (Object, bool) {
+	if stream.err != nil {
+		return nil, false
+	}
+
+	current, err := stream.next()
+	if err != nil {
+		stream.err = err
+		return nil, false
+	}
+
+	stream.index++
+	return current, true
+}
+
+// Err returns the error, which has stopped Stream, it's nil if Stream has been read successfully
+func (stream *Stream) Err() error {
+	if stream.err == io.EOF {
+		return nil
+	}
+	return stream.err
+}
+
+// Iterator returns Iterator over the rest of Stream elements
+func (stream *Stream) Iterator() Iterator {
+	return func(yield func(current Object) bool) {
+		for current, ok := stream.Next(); ok; current, ok = stream.Next() {
+			if !yield(current) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns the lazy Stream of elements, which pass the Predicate
+func (stream *Stream) Filter(cb Predicate) *Stream {
+	if cb == nil {
+		return stream
+	}
+
+	return &Stream{next: func() (Object, error) {
+		for current, ok := stream.Next(); ok; current, ok = stream.Next() {
+			if cb(current, stream.index-1, nil) {
+				return current, nil
+			}
+		}
+		return nil, stream.failure()
+	}}
+}
+
+// Map returns the lazy Stream of elements, transformed by the Callback
+func (stream *Stream) Map(cb Callback) *Stream {
+	if cb == nil {
+		return stream
+	}
+
+	return &Stream{next: func() (Object, error) {
+		if current, ok := stream.Next(); ok {
+			return cb(current, stream.index-1, nil), nil
+		}
+		return nil, stream.failure()
+	}}
+}
+
+// Take returns the lazy Stream of the first n elements
+func (stream *Stream) Take(n int) *Stream {
+	index := 0
+
+	return &Stream{next: func() (Object, error) {
+		if index >= n {
+			return nil, io.EOF
+		}
+
+		if current, ok := stream.Next(); ok {
+			index++
+			return current, nil
+		}
+		return nil, stream.failure()
+	}}
+}
+
+// Each calls the Action for every element of Stream, returns the error, which has stopped Stream
+func (stream *Stream) Each(cb Action) error {
+	for current, ok := stream.Next(); ok; current, ok = stream.Next() {
+		if cb != nil {
+			cb(current, stream.index-1, nil)
+		}
+	}
+
+	return stream.Err()
+}
+
+// Reduce combines elements of Stream into single value by the Collector
+func (stream *Stream) Reduce(cb Collector, initial Object) (Object, error) {
+	memo := initial
+
+	err := stream.Each(func(cur, key, _ Object) {
+		if cb != nil {
+			memo = cb(memo, cur, key, nil)
+		}
+	})
+
+	return memo, err
+}
+
+// Seq reads the rest of Stream into Seq
+func (stream *Stream) Seq() (Seq, error) {
+	seq := Seq{}
+
+	err := stream.Each(func(cur, _, _ Object) {
+		seq = append(seq, cur)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return seq, nil
+}
+
+// WriteJSONL writes every element of Stream as a separate line of JSON
+func (stream *Stream) WriteJSONL(writer io.Writer, opts JSONOptions) error {
+	buffered := bufio.NewWriter(writer)
+	var failure error
+
+	for current, ok := stream.Next(); ok && failure == nil; current, ok = stream.Next() {
+		var data []byte
+		data, failure = createJSONLLine(current, opts)
+
+		if failure == nil {
+			_, failure = buffered.Write(append(data, '\n'))
+		}
+
+		if failure != nil {
+			failure = fmt.Errorf("ugo: element %d: %v", stream.index-1, failure)
+		}
+	}
+
+	return createWriteResult(failure, stream.Err(), buffered.Flush)
+}
+
+// WriteCSV writes every element of Stream as the CSV row, rows might be Seq, []string or map[string]Object,
+// if CSVOptions#Header is set, the header is written first
+func (stream *Stream) WriteCSV(writer io.Writer, opts CSVOptions) error {
+	records := csv.NewWriter(writer)
+	if opts.Comma != 0 {
+		records.Comma = opts.Comma
+	}
+
+	header := opts.Columns
+	headerWritten := false
+	var failure error
+
+	for current, ok := stream.Next(); ok && failure == nil; current, ok = stream.Next() {
+		if opts.Header && !headerWritten {
+			if header == nil {
+				header = createCSVHeader(current)
+			}
+
+			headerWritten = true
+			if failure = records.Write(header); failure != nil {
+				break
+			}
+		}
+
+		var record []string
+		record, failure = createCSVRecord(current, header)
+
+		if failure == nil {
+			failure = records.Write(record)
+		}
+
+		if failure != nil {
+			failure = fmt.Errorf("ugo: element %d: %v", stream.index-1, failure)
+		}
+	}
+
+	return createWriteResult(failure, stream.Err(), func() error {
+		records.Flush()
+		return records.Error()
+	})
+}
+
+/* private methods */
+// failure returns the error of the drained Stream, io.EOF if it has been read successfully
+func (stream *Stream) failure() error {
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+func createWriteResult(failure, streamErr error, flush func() error) error {
+	flushErr := flush()
+
+	if failure != nil {
+		return failure
+	}
+
+	if streamErr != nil {
+		return streamErr
+	}
+
+	return flushErr
+}
+
+func createJSONLValue(data []byte, opts JSONOptions) (Object, error) {
+	if opts.Typed {
+		var element typedElement
+		if err := json.Unmarshal(data, &element); err != nil {
+			return nil, err
+		}
+		return createTypedValue(element)
+	}
+
+	var value interface{}
+	decoder := createNumberDecoder(data)
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return createNumbers(value, opts.Numbers), nil
+}
+
+func createJSONLLine(value Object, opts JSONOptions) ([]byte, error) {
+	if !opts.Typed {
+		return json.Marshal(value)
+	}
+
+	element, err := createTypedElement(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(element)
+}
+
+func createStringSeq(record []string) Seq {
+	seq := make(Seq, len(record))
+	for index, field := range record {
+		seq[index] = field
+	}
+	return seq
+}
+
+// createCSVHeader returns the sorted keys of map row, nil for any other row
+func createCSVHeader(row Object) []string {
+	var header []string
+
+	switch typed := row.(type) {
+	case map[string]Object:
+		for key := range typed {
+			header = append(header, key)
+		}
+	case map[string]interface{}:
+		for key := range typed {
+			header = append(header, key)
+		}
+	}
+
+	sort.Strings(header)
+	return header
+}
+
+func createCSVRecord(row Object, header []string) ([]string, error) {
+	switch typed := row.(type) {
+	case []string:
+		return typed, nil
+	case Seq:
+		return createCSVFields(typed), nil
+	case []interface{}:
+		return createCSVFields(typed), nil
+	case map[string]Object:
+		return createCSVMapRecord(header, func(key string) Object { return typed[key] })
+	case map[string]interface{}:
+		return createCSVMapRecord(header, func(key string) Object { return typed[key] })
+	}
+
+	return nil, fmt.Errorf("type %T can't be written as CSV row", row)
+}
+
+func createCSVMapRecord(header []string, get func(key string) Object) ([]string, error) {
+	if header == nil {
+		return nil, fmt.Errorf("map row needs columns or header")
+	}
+
+	record := make([]string, len(header))
+	for index, column := range header {
+		record[index] = createCSVField(get(column))
+	}
+
+	return record, nil
+}
+
+func createCSVFields(seq Seq) []string {
+	record := make([]string, len(seq))
+	for index, cur := range seq {
+		record[index] = createCSVField(cur)
+	}
+	return record
+}
+
+func createCSVField(value Object) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
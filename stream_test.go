@@ -0,0 +1,240 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"strings"
+	"testing"
+)
+
+type failingReader struct {
+	data string
+}
+
+func (reader *failingReader) Read(buf []byte) (int, error) {
+	if reader.data == "" {
+		return 0, errors.New("broken pipe")
+	}
+
+	count := copy(buf, reader.data)
+	reader.data = reader.data[count:]
+
+	return count, nil
+}
+
+func TestStream(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#StreamJSONL()", func() {
+		g.It("Should decode one value per line, skipping blank lines", func() {
+			seq, err := ReadJSONL(strings.NewReader("{\"id\": 1}\n\n[1, 2]\n\"str\"\n3"), JSONOptions{Numbers: NumberInt64})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{
+				map[string]interface{}{"id": int64(1)},
+				[]interface{}{int64(1), int64(2)},
+				"str",
+				int64(3),
+			})
+		})
+
+		g.It("Should flow through Filter and Map lazily", func() {
+			reads := 0
+			stream := StreamJSONL(strings.NewReader("1\n2\n3\n4\n5\n6\n"), JSONOptions{Numbers: NumberInt64})
+
+			res, err := stream.Map(func(cur, _, _ Object) Object {
+				reads++
+				return cur.(int64) * 10
+			}).Filter(func(cur, _, _ Object) bool {
+				return cur.(int64) > 20
+			}).Take(2).Seq()
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(res).Equal(Seq{int64(30), int64(40)})
+			g.Assert(reads).Equal(4)
+		})
+
+		g.It("Should stop with the error of malformed line", func() {
+			stream := StreamJSONL(strings.NewReader("1\n{oops}\n3\n"), JSONOptions{})
+			seq := Seq{}
+
+			for current := range stream.Iterator() {
+				seq = append(seq, current)
+			}
+
+			g.Assert(seq).Equal(Seq{1.0})
+			g.Assert(stream.Err() == nil).IsFalse()
+			g.Assert(strings.HasPrefix(stream.Err().Error(), "ugo: line 2:")).IsTrue()
+
+			_, err := ReadJSONL(strings.NewReader("1 2\n"), JSONOptions{})
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("Should stop with the error of reader", func() {
+			_, err := ReadJSONL(&failingReader{data: "1\n2\n"}, JSONOptions{})
+
+			g.Assert(err.Error()).Equal("broken pipe")
+		})
+
+		g.It("Should round-trip typed lines", func() {
+			var buf bytes.Buffer
+			seq := Seq{1, "two", uint8(3), Seq{4.5}}
+
+			g.Assert(WriteJSONL(&buf, seq, JSONOptions{Typed: true}) == nil).IsTrue()
+
+			res, err := ReadJSONL(&buf, JSONOptions{Typed: true})
+			g.Assert(err == nil).IsTrue()
+			g.Assert(res).Equal(seq)
+		})
+	})
+
+	g.Describe("#WriteJSONL()", func() {
+		g.It("Should write every element as a separate line", func() {
+			var buf bytes.Buffer
+
+			err := WriteJSONL(&buf, Seq{1, map[string]interface{}{"a": "b"}, nil}, JSONOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(buf.String()).Equal("1\n{\"a\":\"b\"}\nnull\n")
+		})
+
+		g.It("Should return the error of element, which can't be encoded", func() {
+			var buf bytes.Buffer
+
+			err := WriteJSONL(&buf, Seq{1, func() {}}, JSONOptions{})
+
+			g.Assert(err == nil).IsFalse()
+			g.Assert(strings.HasPrefix(err.Error(), "ugo: element 1:")).IsTrue()
+			g.Assert(buf.String()).Equal("1\n")
+		})
+	})
+
+	g.Describe("#StreamCSV()", func() {
+		g.It("Should read rows as Seq", func() {
+			seq, err := ReadCSV(strings.NewReader("a,b\n1,\"2,3\"\n"), CSVOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{Seq{"a", "b"}, Seq{"1", "2,3"}})
+		})
+
+		g.It("Should read rows as maps keyed by header", func() {
+			seq, err := ReadCSV(strings.NewReader("id;team\n1;red\n2;blue\n"), CSVOptions{Header: true, Comma: ';'})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{
+				map[string]Object{"id": "1", "team": "red"},
+				map[string]Object{"id": "2", "team": "blue"},
+			})
+		})
+
+		g.It("Should use given columns instead of the header", func() {
+			seq, err := ReadCSV(strings.NewReader("ID,TEAM\n1,red\n"), CSVOptions{Header: true, Columns: []string{"id", "team"}})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{map[string]Object{"id": "1", "team": "red"}})
+		})
+
+		g.It("Should key rows without header by given columns", func() {
+			seq, err := ReadCSV(strings.NewReader("1,red\n2,blue\n"), CSVOptions{Columns: []string{"id", "team"}})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(seq).Equal(Seq{
+				map[string]Object{"id": "1", "team": "red"},
+				map[string]Object{"id": "2", "team": "blue"},
+			})
+		})
+
+		g.It("Should return an error for the row of wrong length", func() {
+			_, err := ReadCSV(strings.NewReader("id,team\n1\n"), CSVOptions{Header: true})
+
+			g.Assert(err.Error()).Equal("ugo: line 2: expected 2 fields, got 1")
+		})
+	})
+
+	g.Describe("#WriteCSV()", func() {
+		g.It("Should write Seq rows", func() {
+			var buf bytes.Buffer
+
+			err := WriteCSV(&buf, Seq{Seq{"a", 1, nil}, []string{"b", "c,d"}}, CSVOptions{})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(buf.String()).Equal("a,1,\nb,\"c,d\"\n")
+		})
+
+		g.It("Should write map rows with the sorted header", func() {
+			var buf bytes.Buffer
+			seq := Seq{map[string]Object{"team": "red", "id": 1}, map[string]Object{"id": 2}}
+
+			err := WriteCSV(&buf, seq, CSVOptions{Header: true})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(buf.String()).Equal("id,team\n1,red\n2,\n")
+		})
+
+		g.It("Should stream rows from CSV to CSV", func() {
+			var buf bytes.Buffer
+
+			err := StreamCSV(strings.NewReader("id,score\n1,10\n2,30\n"), CSVOptions{Header: true}).Filter(func(cur, _, _ Object) bool {
+				return cur.(map[string]Object)["score"] != "10"
+			}).WriteCSV(&buf, CSVOptions{Header: true, Columns: []string{"score", "id"}})
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(buf.String()).Equal("score,id\n30,2\n")
+		})
+
+		g.It("Should return an error for the map row without header", func() {
+			var buf bytes.Buffer
+
+			err := WriteCSV(&buf, Seq{map[string]Object{"id": 1}}, CSVOptions{})
+
+			g.Assert(err.Error()).Equal("ugo: element 0: map row needs columns or header")
+		})
+	})
+
+	g.Describe("#Stream", func() {
+		g.It("Should pass running index to callbacks", func() {
+			keys := Seq{}
+			err := NewStream(Seq{"a", "b", "c"}).Each(func(_, key, _ Object) { keys = append(keys, key) })
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(keys).Equal(Seq{0, 1, 2})
+		})
+
+		g.It("Should reduce elements of channel", func() {
+			ch := make(chan Object, 3)
+			ch <- 1
+			ch <- 2
+			ch <- 3
+			close(ch)
+
+			sum, err := StreamChan(ch).Reduce(func(memo, cur, _, _ Object) Object { return memo.(int) + cur.(int) }, 0)
+
+			g.Assert(err == nil).IsTrue()
+			g.Assert(sum).Equal(6)
+		})
+	})
+}
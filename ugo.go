@@ -46,9 +46,7 @@ package ugo
 import (
 	sorter "github.com/alxrm/ugo/timsort"
 	"math"
-	"math/rand"
 	"reflect"
-	"time"
 )
 
 // Object is an alias type for interface{}
@@ -110,6 +108,10 @@ type Predicate func(current, currentKey, src Object) bool
 // * Seq list
 type Action func(current, currentKey, src Object)
 
+// Iterator is an alias type for function, which passes elements to yield one by one,
+// until they're over or yield returns false, it can be used in range-over-func loops
+type Iterator func(yield func(current Object) bool)
+
 const (
 	toMin int = -1 /** constant value for incrementing */
 	toMax int = 1  /** constant value for decrementing */
@@ -208,7 +210,7 @@ func Reject(seq Seq, cb Predicate) Seq {
 		return seq
 	}
 
-	return Filter(seq, negate(cb))
+	return Filter(seq, Negate(cb))
 }
 
 // Reduce makes single value from all of the slice elements, iterating from left
@@ -325,7 +327,10 @@ func IndexOf(seq Seq, target Object, isSorted bool, cb Comparator) int {
 	}
 
 	if isSorted {
-		return createBinarySearch(seq, target, cb, len(seq))
+		if index, found := BinarySearch(seq, target, cb); found {
+			return index
+		}
+		return -1
 	}
 
 	equalityPredicate := func(cur, _, _ Object) bool { return cb(cur, target) == 0 }
@@ -341,6 +346,52 @@ func LastIndexOf(seq Seq, target Object, cb Comparator) int {
 	return FindLastIndex(seq, equalityPredicate)
 }
 
+// LastIndexOfSorted founds index of the last element, which equals to passed one(target), via binary search
+// NOTE: slice should be sorted by the same Comparator
+func LastIndexOfSorted(seq Seq, target Object, cb Comparator) int {
+	if index, found := BinarySearchLast(seq, target, cb); found {
+		return index
+	}
+	return -1
+}
+
+// BinarySearch returns index of the first element, which equals to passed one(target), and true,
+// if there is no such element, it returns the position target should be inserted at and false
+// NOTE: slice should be sorted by the same Comparator
+func BinarySearch(seq Seq, target Object, cb Comparator) (int, bool) {
+	if cb == nil {
+		return -1, false
+	}
+
+	index := createLowerBound(seq, target, cb, len(seq))
+	return index, index < len(seq) && cb(seq[index], target) == 0
+}
+
+// BinarySearchLast returns index of the last element, which equals to passed one(target), and true,
+// if there is no such element, it returns the position target should be inserted at and false
+// NOTE: slice should be sorted by the same Comparator
+func BinarySearchLast(seq Seq, target Object, cb Comparator) (int, bool) {
+	if cb == nil {
+		return -1, false
+	}
+
+	index := createUpperBound(seq, target, cb, len(seq))
+	if index > 0 && cb(seq[index-1], target) == 0 {
+		return index - 1, true
+	}
+
+	return index, false
+}
+
+// SortedIndex returns the lowest position at which target should be inserted to keep the slice sorted
+// NOTE: slice should be sorted by the same Comparator
+func SortedIndex(seq Seq, target Object, cb Comparator) int {
+	if cb == nil {
+		return -1
+	}
+	return createLowerBound(seq, target, cb, len(seq))
+}
+
 // Contains returns true if slice contains element, which equals to passed one(target)
 // NOTE: if slice is sorted, this method can use better search algorithm
 func Contains(seq Seq, target Object, isSorted bool, cb Comparator) bool {
@@ -468,13 +519,14 @@ func Union(seq, other Seq, cb Comparator) Seq {
 		return Seq{}
 	}
 
-	result := Concat(seq, other)
+	result := ConcatCopy(seq, other)
 
 	return Uniq(result, cb)
 }
 
 // SortBy returns sorted slice, uses very powerful timsort* algorithm
-// *timsort obtained from: https://github.com/psilva261/timsort
+// *timsort obtained from: https://github.com/psilva261/timsort, see #SortWith for the other algorithms
+// NOTE: it sorts given slice in place, use #SortedCopy to keep it untouched
 func SortBy(seq Seq, cb Comparator) Seq {
 	if seq == nil {
 		return Seq{}
@@ -486,6 +538,14 @@ func SortBy(seq Seq, cb Comparator) Seq {
 	return seq
 }
 
+// SortedCopy returns sorted copy of slice (see #SortBy)
+func SortedCopy(seq Seq, cb Comparator) Seq {
+	if seq == nil {
+		return Seq{}
+	}
+	return SortBy(createCopy(seq), cb)
+}
+
 // CountBy returns map, which values are count of certain kind of values,
 // and keys are names of this kinds
 func CountBy(seq Seq, cb Callback) (result map[string]int) {
@@ -536,22 +596,23 @@ func GroupBy(seq Seq, cb Callback) map[Object]Seq {
 }
 
 // Remove takes an element from given position in slice
+// NOTE: it always returns a new slice, given one stays untouched
 func Remove(seq Seq, position int) Seq {
 	if IsEmpty(seq) {
 		return Seq{}
 	}
 	position = fixPosition(position, len(seq)-1)
 
-	bef := NewSeq(len(seq[:position]))
-	aft := NewSeq(len(seq[position+1:]))
+	result := NewSeq(len(seq) - 1)
 
-	copy(bef, seq[:position])
-	copy(aft, seq[position+1:])
+	copy(result, seq[:position])
+	copy(result[position:], seq[position+1:])
 
-	return Concat(bef, aft)
+	return result
 }
 
 // Insert pushes an element into given position in slice
+// NOTE: it may change given slice, use #InsertCopy to keep it untouched
 func Insert(seq Seq, target Object, position int) Seq {
 	if seq == nil {
 		return Seq{}
@@ -565,7 +626,24 @@ func Insert(seq Seq, target Object, position int) Seq {
 	return seq
 }
 
+// InsertCopy returns copy of slice with an element pushed into given position (see #Insert)
+func InsertCopy(seq Seq, target Object, position int) Seq {
+	if seq == nil {
+		return Seq{}
+	}
+	position = fixPosition(position, len(seq))
+
+	result := NewSeq(len(seq) + 1)
+
+	copy(result, seq[:position])
+	result[position] = target
+	copy(result[position+1:], seq[position:])
+
+	return result
+}
+
 // Concat adds another slice to the end of given slice
+// NOTE: it may reuse the memory of given slice, use #ConcatCopy to keep it untouched
 func Concat(seq, next Seq) Seq {
 	if seq == nil {
 		return Seq{}
@@ -577,28 +655,50 @@ func Concat(seq, next Seq) Seq {
 	return append(seq, next...)
 }
 
+// ConcatCopy returns a new slice, which contains elements of given slice and then of another one (see #Concat)
+func ConcatCopy(seq, next Seq) Seq {
+	if seq == nil {
+		return Seq{}
+	}
+
+	result := make(Seq, 0, len(seq)+len(next))
+	result = append(result, seq...)
+
+	return append(result, next...)
+}
+
 // Shuffle returns shuffled slice
+// NOTE: it shuffles given slice in place, use #ShuffledCopy to keep it untouched
 func Shuffle(seq Seq) Seq {
+	return ShuffleWith(seq, nil)
+}
+
+// ShuffleWith returns slice shuffled with numbers from given Rand (see #Shuffle),
+// if Rand is not passed, it uses the default one
+func ShuffleWith(seq Seq, rng Rand) Seq {
 	if seq == nil {
 		return Seq{}
 	}
-	return createShuffle(seq)
+	return createShuffle(seq, fixRand(rng))
 }
 
 // ShuffledCopy returns shuffled copy of slice
 func ShuffledCopy(seq Seq) Seq {
+	return ShuffledCopyWith(seq, nil)
+}
+
+// ShuffledCopyWith returns copy of slice shuffled with numbers from given Rand (see #ShuffledCopy),
+// if Rand is not passed, it uses the default one
+func ShuffledCopyWith(seq Seq, rng Rand) Seq {
 	if seq == nil {
 		return Seq{}
 	}
 
-	length := len(seq)
-	copied := NewSeq(length)
-	copy(copied, seq)
-
-	return createShuffle(copied)
+	return createShuffle(createCopy(seq), fixRand(rng))
 }
 
 // Reverse returns reversed slice
+// NOTE: it reverses given slice in place, use #ReversedCopy to keep it untouched
 func Reverse(seq Seq) Seq {
 	if seq == nil {
 		return Seq{}
@@ -615,11 +715,31 @@ func ReversedCopy(seq Seq) Seq {
 		return Seq{}
 	}
 
-	length := len(seq)
-	copied := NewSeq(length)
-	copy(copied, seq)
+	return createReverse(createCopy(seq), len(seq))
+}
+
+// First returns the first n elements of slice, or all of them if there are fewer
+func First(seq Seq, n int) Seq {
+	if IsEmpty(seq) || n <= 0 {
+		return Seq{}
+	}
+	if n > len(seq) {
+		n = len(seq)
+	}
+
+	return createCopy(seq[:n])
+}
+
+// Last returns the last n elements of slice, or all of them if there are fewer
+func Last(seq Seq, n int) Seq {
+	if IsEmpty(seq) || n <= 0 {
+		return Seq{}
+	}
+	if n > len(seq) {
+		n = len(seq)
+	}
 
-	return createReverse(copied, length)
+	return createCopy(seq[len(seq)-n:])
 }
 
 // EqualsStrict checks whether both of the given slices are strictly equal,
@@ -639,25 +759,37 @@ func EqualsStrict(seqLeft, seqRight Seq, cb Comparator) bool {
 }
 
 // EqualsNotStrict checks whether both of the given slices are equal, but not strictly,
-// e. g. they the same values, but positions can be different
+// e. g. they the same values, but positions can be different,
+// Comparator only needs to tell equal values, so it takes O(n^2) comparisons without extra allocations,
+// use #EqualsNotStrictSorted or #EqualsNotStrictBy for the large slices
 func EqualsNotStrict(seqLeft, seqRight Seq, cb Comparator) bool {
 	lengthLeft := len(seqLeft)
 	lengthRight := len(seqRight)
-	target := NewSeq(lengthLeft)
-	foundIndex := -1
 
 	if lengthLeft != lengthRight || cb == nil {
 		return false
 	}
 
+	target := NewSeq(lengthRight)
 	copy(target, seqRight)
 
 	for _, value := range seqLeft {
-		if foundIndex = IndexOf(target, value, false, cb); foundIndex != -1 {
-			target = Remove(target, foundIndex)
-		} else {
+		foundIndex := -1
+		for index, cur := range target {
+			if cb(cur, value) == equal {
+				foundIndex = index
+				break
+			}
+		}
+
+		if foundIndex == -1 {
 			return false
 		}
+
+		// the found value is swapped with the last one, so the rest of them are left in place
+		last := len(target) - 1
+		target[foundIndex] = target[last]
+		target = target[:last]
 	}
 
 	return true
@@ -667,11 +799,16 @@ func EqualsNotStrict(seqLeft, seqRight Seq, cb Comparator) bool {
 
 // Random returns the random number in given range
 func Random(min, max float64) int {
+	return RandomWith(min, max, nil)
+}
+
+// RandomWith returns the random number in given range, taken from given Rand (see #Random),
+// if Rand is not passed, it uses the default one
+func RandomWith(min, max float64, rng Rand) int {
 	if min == max {
 		return 0
 	}
 
-	rand.Seed(time.Now().UnixNano())
 	min = fixNumber(min)
 	max = fixNumber(max)
 
@@ -679,7 +816,7 @@ func Random(min, max float64) int {
 		min, max = max, min
 	}
 
-	res := min + math.Floor(rand.Float64()*(max-min+1))
+	res := min + math.Floor(fixRand(rng).Float64()*(max-min+1))
 	if math.IsNaN(res) {
 		return 0
 	}
@@ -697,21 +834,19 @@ func IsSlice(target Object) bool {
 	return reflect.ValueOf(target).Kind() == reflect.Slice
 }
 
-// From returns Seq from given object, filling the resulting Seq via reflection
-// NOTE: You should possibly avoid using this one
-func From(target Object, size int) Seq {
-	if !IsSlice(target) || size < 0 {
-		return Seq{}
-	}
-
-	s := reflect.ValueOf(target)
-	res := make(Seq, size)
-
-	for i := 0; i < size; i++ {
-		res[i] = s.Index(i).Interface()
+// From returns Seq from given slice, array, map, receiving channel or Iterator, the length is inferred from target,
+// maps are turned into the pairs of Seq{key, value} ordered by keys, channels are read until they are closed,
+// common slices like []int, []string, []float64 or []byte are copied without reflection,
+// the optional size limits the count of taken values, negative size returns empty Seq
+func From(target Object, size ...int) Seq {
+	limit := -1
+	if len(size) > 0 {
+		if limit = size[0]; limit < 0 {
+			return Seq{}
+		}
 	}
 
-	return res
+	return createSeqFrom(target, limit)
 }
 
 /* private methods */
@@ -758,16 +893,32 @@ func createReduce(seq Seq, cb Collector, memo Object, startPoint, direction, len
 	return result
 }
 
-// createShuffle returns slice shuffled by Fisher-Yates algorithm
-func createShuffle(seq Seq) Seq {
-	for i := range seq {
-		j := Random(0, float64(i))
+// createShuffle returns slice shuffled by Fisher-Yates algorithm,
+// every swap takes the next number of the same Rand, so all permutations are equally likely
+func createShuffle(seq Seq, rng Rand) Seq {
+	for i := len(seq) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
 		seq[i], seq[j] = seq[j], seq[i]
 	}
 
 	return seq
 }
 
+// createCopy returns a new slice with the same elements
+func createCopy(seq Seq) Seq {
+	copied := NewSeq(len(seq))
+	copy(copied, seq)
+	return copied
+}
+
+// createKey returns the result of key Callback for the element, or the element itself if Callback is not passed
+func createKey(value Object, index int, seq Seq, key Callback) Object {
+	if key == nil {
+		return value
+	}
+	return key(value, index, seq)
+}
+
 // createReverse returns the slice, shuffled in O(n/2) operations
 func createReverse(seq Seq, length int) Seq {
 	for left, right := 0, length-1; left < right; left, right = left+1, right-1 {
@@ -801,31 +952,42 @@ func createPredicateSearch(seq Seq, cb Predicate, startPoint, direction, length
 	return
 }
 
-// createBinarySearch returns the index of the value we want to find,
-// it uses the Binary Search algorithm to reduce iteration count
-// this assumes that we operating with sorted Sequence
-func createBinarySearch(sortedSeq Seq, target Object, cb Comparator, length int) int {
-	resIndex := -1
-
+// createLowerBound returns the position of the first element,
+// which is not less than target, it assumes that the slice is sorted
+func createLowerBound(sortedSeq Seq, target Object, cb Comparator, length int) int {
 	lo := 0
 	hi := length
 
 	for lo < hi {
-		mid := (lo + hi) >> 1
+		mid := int(uint(lo+hi) >> 1)
 
-		if cb(sortedSeq[mid], target) == 0 {
-			resIndex = mid
-			break
+		if cb(sortedSeq[mid], target) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
 		}
+	}
 
-		if cb(sortedSeq[mid], target) < 0 {
+	return lo
+}
+
+// createUpperBound returns the position of the first element,
+// which is larger than target, it assumes that the slice is sorted
+func createUpperBound(sortedSeq Seq, target Object, cb Comparator, length int) int {
+	lo := 0
+	hi := length
+
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+
+		if cb(sortedSeq[mid], target) <= 0 {
 			lo = mid + 1
 		} else {
 			hi = mid
 		}
 	}
 
-	return resIndex
+	return lo
 }
 
 // lessThen returns function we can use for sorting or comparing,
@@ -834,11 +996,6 @@ func lessThan(cb Comparator) func(l, r interface{}) bool {
 	return func(l, r interface{}) bool { return cb(l, r) < 0 }
 }
 
-// negate returns the given Predicate but with opposite result
-func negate(cb Predicate) Predicate {
-	return func(cur, index, list Object) bool { return !cb(cur, index, list) }
-}
-
 // sgn returns the sign of the passed number, which can be, as follows,
 // -1, 0, 1 (negative, zero, positive)
 func sgn(num int) int {
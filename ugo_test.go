@@ -221,6 +221,12 @@ func TestSingleValues(t *testing.T) {
 			g.Assert(IndexOf(nil, 0, false, intComparator)).Equal(-1)
 			g.Assert(IndexOf(nil, 0, false, nil)).Equal(-1)
 		})
+
+		g.It("Should return the same index for duplicates in both search modes", func() {
+			g.Assert(IndexOf(inSeq, 2, true, intComparator)).Equal(IndexOf(inSeq, 2, false, intComparator))
+			g.Assert(IndexOf(inSeq, 10, true, intComparator)).Equal(6)
+			g.Assert(IndexOf(Seq{5, 5, 5, 5, 5}, 5, true, intComparator)).Equal(0)
+		})
 	})
 
 	g.Describe("#LastIndexOf()", func() {
@@ -235,6 +241,72 @@ func TestSingleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#LastIndexOfSorted()", func() {
+		g.It("Should use binary search to find last index of target value", func() {
+			g.Assert(LastIndexOfSorted(inSeq, 10, intComparator)).Equal(7)
+			g.Assert(LastIndexOfSorted(inSeq, 2, intComparator)).Equal(1)
+			g.Assert(LastIndexOfSorted(inSeq, 120, intComparator)).Equal(9)
+			g.Assert(LastIndexOfSorted(inSeq, 88, intComparator)).Equal(-1)
+			g.Assert(LastIndexOfSorted(nil, 0, intComparator)).Equal(-1)
+			g.Assert(LastIndexOfSorted(inSeq, 10, nil)).Equal(-1)
+		})
+	})
+
+	g.Describe("#BinarySearch()", func() {
+		g.It("Should return the first index of target value or its insertion point", func() {
+			index, found := BinarySearch(inSeq, 10, intComparator)
+			g.Assert(index).Equal(6)
+			g.Assert(found).IsTrue()
+
+			index, found = BinarySearch(inSeq, 9, intComparator)
+			g.Assert(index).Equal(6)
+			g.Assert(found).IsFalse()
+
+			index, found = BinarySearch(inSeq, 200, intComparator)
+			g.Assert(index).Equal(10)
+			g.Assert(found).IsFalse()
+
+			index, found = BinarySearch(nil, 1, intComparator)
+			g.Assert(index).Equal(0)
+			g.Assert(found).IsFalse()
+
+			index, found = BinarySearch(inSeq, 1, nil)
+			g.Assert(index).Equal(-1)
+			g.Assert(found).IsFalse()
+		})
+	})
+
+	g.Describe("#BinarySearchLast()", func() {
+		g.It("Should return the last index of target value or its insertion point", func() {
+			index, found := BinarySearchLast(inSeq, 10, intComparator)
+			g.Assert(index).Equal(7)
+			g.Assert(found).IsTrue()
+
+			index, found = BinarySearchLast(inSeq, 9, intComparator)
+			g.Assert(index).Equal(6)
+			g.Assert(found).IsFalse()
+
+			index, found = BinarySearchLast(inSeq, 0, intComparator)
+			g.Assert(index).Equal(0)
+			g.Assert(found).IsFalse()
+
+			index, found = BinarySearchLast(inSeq, 1, nil)
+			g.Assert(index).Equal(-1)
+			g.Assert(found).IsFalse()
+		})
+	})
+
+	g.Describe("#SortedIndex()", func() {
+		g.It("Should return the lowest position target should be inserted at", func() {
+			g.Assert(SortedIndex(inSeq, 10, intComparator)).Equal(6)
+			g.Assert(SortedIndex(inSeq, 11, intComparator)).Equal(8)
+			g.Assert(SortedIndex(inSeq, -5, intComparator)).Equal(0)
+			g.Assert(SortedIndex(inSeq, 500, intComparator)).Equal(10)
+			g.Assert(SortedIndex(nil, 5, intComparator)).Equal(0)
+			g.Assert(SortedIndex(inSeq, 5, nil)).Equal(-1)
+		})
+	})
+
 	g.Describe("#EqualsStrict()", func() {
 		g.It("Should return true if sliceA == sliceB, e. g. order and elements", func() {
 			g.Assert(EqualsStrict(inSeq, inSeq, intComparator)).IsTrue()
@@ -259,6 +331,20 @@ func TestSingleValues(t *testing.T) {
 			g.Assert(EqualsNotStrict(nil, nil, intComparator)).IsTrue()
 			g.Assert(EqualsNotStrict(nil, nil, nil)).IsFalse()
 		})
+
+		g.It("Should count duplicates and keep slices untouched", func() {
+			right := Seq{2, 1, 2, 3}
+			isSame := func(l, r Object) int {
+				if l == r {
+					return 0
+				}
+				return 1
+			}
+
+			g.Assert(EqualsNotStrict(Seq{1, 2, 3, 2}, right, isSame)).IsTrue()
+			g.Assert(EqualsNotStrict(Seq{1, 2, 3, 3}, right, isSame)).IsFalse()
+			g.Assert(right).Equal(Seq{2, 1, 2, 3})
+		})
 	})
 }
 
@@ -387,6 +473,20 @@ func TestMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#SortedCopy()", func() {
+		g.It("Should return sorted copy of Seq, leaving the Seq untouched", func() {
+			inSeq := Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}
+			outSeqSorted := Seq{2, 2, 4, 6, 7, 8, 10, 10, 17, 120}
+			empty := Seq{}
+
+			g.Assert(SortedCopy(inSeq, intComparator)).Equal(outSeqSorted)
+			g.Assert(inSeq).Equal(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17})
+			g.Assert(SortedCopy(inSeq, nil)).Equal(inSeq)
+			g.Assert(SortedCopy(nil, intComparator)).Equal(empty)
+			g.Assert(SortedCopy(nil, nil)).Equal(empty)
+		})
+	})
+
 	g.Describe("#CountBy()", func() {
 		g.It("Should return map with countings, e. g. keys - callback result, value - number of same results", func() {
 			inSeq := Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}
@@ -411,6 +511,7 @@ func TestMultipleValues(t *testing.T) {
 			g.Assert(Remove(outSeqRemoved, -1)).Equal(outSeqRemovedFirst)
 			g.Assert(Remove(outSeqRemovedFirst, 30)).Equal(outSeqRemovedLast)
 			g.Assert(Remove(nil, -1)).Equal(empty)
+			g.Assert(inSeq).Equal(Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17})
 		})
 	})
 
@@ -429,6 +530,20 @@ func TestMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#InsertCopy()", func() {
+		g.It("Should return copy of Seq with new value, leaving the Seq untouched", func() {
+			inSeq := make(Seq, 6, 10)
+			copy(inSeq, Seq{2, 4, 6, 7, 8, 10})
+			empty := Seq{}
+
+			g.Assert(InsertCopy(inSeq, 20, 1)).Equal(Seq{2, 20, 4, 6, 7, 8, 10})
+			g.Assert(InsertCopy(inSeq, 92, -1)).Equal(Seq{92, 2, 4, 6, 7, 8, 10})
+			g.Assert(InsertCopy(inSeq, 22, 30)).Equal(Seq{2, 4, 6, 7, 8, 10, 22})
+			g.Assert(InsertCopy(nil, nil, -1)).Equal(empty)
+			g.Assert(inSeq[:cap(inSeq)]).Equal(Seq{2, 4, 6, 7, 8, 10, nil, nil, nil, nil})
+		})
+	})
+
 	g.Describe("#Concat()", func() {
 		g.It("Should return slice, with appended another slice", func() {
 			inSeq := Seq{2, 4, 6, 7, 8, 10}
@@ -443,6 +558,23 @@ func TestMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#ConcatCopy()", func() {
+		g.It("Should return a new slice with both slices, leaving them untouched", func() {
+			inSeq := make(Seq, 6, 10)
+			copy(inSeq, Seq{2, 4, 6, 7, 8, 10})
+			nextSeq := Seq{777, 1992}
+			outSeq := Seq{2, 4, 6, 7, 8, 10, 777, 1992}
+			empty := Seq{}
+
+			g.Assert(ConcatCopy(inSeq, nextSeq)).Equal(outSeq)
+			g.Assert(ConcatCopy(inSeq, nil)).Equal(inSeq)
+			g.Assert(ConcatCopy(nil, outSeq)).Equal(empty)
+			g.Assert(ConcatCopy(nil, nil)).Equal(empty)
+			g.Assert(inSeq[:cap(inSeq)]).Equal(Seq{2, 4, 6, 7, 8, 10, nil, nil, nil, nil})
+			g.Assert(nextSeq).Equal(Seq{777, 1992})
+		})
+	})
+
 	g.Describe("#Shuffle()", func() {
 		g.It("Should return shuffled Seq", func() {
 			inSeq := Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}
@@ -493,6 +625,34 @@ func TestMultipleValues(t *testing.T) {
 		})
 	})
 
+	g.Describe("#First()", func() {
+		g.It("Should return the first n elements of Seq", func() {
+			inSeq := Seq{2, 4, 6, 7}
+			empty := Seq{}
+
+			first := First(inSeq, 2)
+			first[0] = 100
+
+			g.Assert(first).Equal(Seq{100, 4})
+			g.Assert(inSeq).Equal(Seq{2, 4, 6, 7})
+			g.Assert(First(inSeq, 10)).Equal(inSeq)
+			g.Assert(First(inSeq, 0)).Equal(empty)
+			g.Assert(First(nil, 1)).Equal(empty)
+		})
+	})
+
+	g.Describe("#Last()", func() {
+		g.It("Should return the last n elements of Seq", func() {
+			inSeq := Seq{2, 4, 6, 7}
+			empty := Seq{}
+
+			g.Assert(Last(inSeq, 2)).Equal(Seq{6, 7})
+			g.Assert(Last(inSeq, 10)).Equal(inSeq)
+			g.Assert(Last(inSeq, -1)).Equal(empty)
+			g.Assert(Last(nil, 1)).Equal(empty)
+		})
+	})
+
 	g.Describe("#Uniq()", func() {
 		g.It("Should return Seq with no duplicates", func() {
 			inSeq := Seq{2, 4, 6, 7, 8, 10, 120, 10, 2, 17}
@@ -555,6 +715,14 @@ func TestMultipleValues(t *testing.T) {
 			g.Assert(Union(inSeq, nil, nil)).Equal(empty)
 			g.Assert(Union(nil, difSeq, nil)).Equal(empty)
 		})
+
+		g.It("Should leave the memory of given slice untouched", func() {
+			inSeq := make(Seq, 2, 4)
+			copy(inSeq, Seq{1, 2})
+
+			g.Assert(Union(inSeq, Seq{3, 4}, intComparator)).Equal(Seq{1, 2, 3, 4})
+			g.Assert(inSeq[:cap(inSeq)]).Equal(Seq{1, 2, nil, nil})
+		})
 	})
 
 	g.Describe("#Without()", func() {
@@ -0,0 +1,310 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo
+
+// Vector is the immutable sequence, every change returns a new Vector,
+// which shares the unchanged parts with the previous one,
+// so it's safe to use the same Vector from many goroutines
+//
+// It's backed by balanced tree, so most of operations take O(log n),
+// the zero value is an empty Vector
+type Vector struct {
+	root *vectorNode // root is the root of balanced tree, nil for an empty Vector
+}
+
+// NewVector creates a new Vector, filled with elements of given slice
+func NewVector(seq Seq) Vector {
+	return Vector{root: createVectorTree(seq)}
+}
+
+// Len returns the count of elements in Vector
+func (vec Vector) Len() int {
+	return vectorSize(vec.root)
+}
+
+// At returns the element on given position, or nil if position is out of bounds
+func (vec Vector) At(position int) Object {
+	if position < 0 || position >= vec.Len() {
+		return nil
+	}
+
+	node := vec.root
+	for {
+		left := vectorSize(node.left)
+		if position < left {
+			node = node.left
+		} else if position > left {
+			position -= left + 1
+			node = node.right
+		} else {
+			return node.value
+		}
+	}
+}
+
+// Set returns a new Vector with element on given position replaced by target,
+// returns the same Vector if position is out of bounds
+func (vec Vector) Set(position int, target Object) Vector {
+	if position < 0 || position >= vec.Len() {
+		return vec
+	}
+	return Vector{root: createVectorSet(vec.root, position, target)}
+}
+
+// Append returns a new Vector with target pushed to the end
+func (vec Vector) Append(target Object) Vector {
+	return vec.Insert(target, vec.Len())
+}
+
+// Insert returns a new Vector with target pushed into given position
+func (vec Vector) Insert(target Object, position int) Vector {
+	position = fixPosition(position, vec.Len())
+	left, right := splitVectorTree(vec.root, position)
+	return Vector{root: joinVectorTree(left, target, right)}
+}
+
+// Remove returns a new Vector without an element on given position
+func (vec Vector) Remove(position int) Vector {
+	if vec.Len() == 0 {
+		return vec
+	}
+
+	position = fixPosition(position, vec.Len()-1)
+	left, right := splitVectorTree(vec.root, position)
+	_, right = splitVectorTree(right, 1)
+
+	return Vector{root: concatVectorTree(left, right)}
+}
+
+// Slice returns a new Vector, which contains elements from position from to position to (exclusive)
+func (vec Vector) Slice(from, to int) Vector {
+	from = fixPosition(from, vec.Len())
+	to = fixPosition(to, vec.Len())
+
+	if from >= to {
+		return Vector{}
+	}
+
+	rest, _ := splitVectorTree(vec.root, to)
+	_, result := splitVectorTree(rest, from)
+
+	return Vector{root: result}
+}
+
+// Concat returns a new Vector, which contains elements of the other Vector after its own ones
+func (vec Vector) Concat(other Vector) Vector {
+	return Vector{root: concatVectorTree(vec.root, other.root)}
+}
+
+// Each calls cb Action on each element
+func (vec Vector) Each(cb Action) {
+	if cb == nil {
+		return
+	}
+
+	index := 0
+	eachVectorNode(vec.root, func(value Object) {
+		cb(value, index, vec)
+		index++
+	})
+}
+
+// Iterator returns Iterator over Vector elements, it walks the tree lazily without copying,
+// so it's the cheap way to pass Vector to the read-only functions like #FindIterator or #ReduceIterator
+func (vec Vector) Iterator() Iterator {
+	return func(yield func(current Object) bool) {
+		iterateVectorNode(vec.root, yield)
+	}
+}
+
+// Seq returns a new slice, filled with Vector elements,
+// it's the way to pass Vector to any of the functions, which accept Seq
+// NOTE: it copies all of the elements in O(n) on every call, use #Vector.Each or #Vector.Iterator to walk through them
+func (vec Vector) Seq() Seq {
+	result := make(Seq, 0, vec.Len())
+	eachVectorNode(vec.root, func(value Object) { result = append(result, value) })
+	return result
+}
+
+// Chain is a start point for chaining behaviour over the copy of Vector elements
+// NOTE: it copies all of the elements in O(n) (see #Vector.Seq)
+func (vec Vector) Chain() *ChainWrapper {
+	return Chain(vec.Seq())
+}
+
+/* private methods */
+// vectorNode is the node of immutable AVL tree, ordered by position
+type vectorNode struct {
+	value       Object
+	size        int
+	height      int
+	left, right *vectorNode
+}
+
+// createVectorNode returns a new node with size and height calculated from its branches
+func createVectorNode(value Object, left, right *vectorNode) *vectorNode {
+	height := vectorHeight(left)
+	if rightHeight := vectorHeight(right); rightHeight > height {
+		height = rightHeight
+	}
+
+	return &vectorNode{
+		value:  value,
+		size:   vectorSize(left) + vectorSize(right) + 1,
+		height: height + 1,
+		left:   left,
+		right:  right,
+	}
+}
+
+// createVectorTree returns the balanced tree, built from given slice
+func createVectorTree(seq Seq) *vectorNode {
+	if len(seq) == 0 {
+		return nil
+	}
+
+	mid := len(seq) / 2
+	return createVectorNode(seq[mid], createVectorTree(seq[:mid]), createVectorTree(seq[mid+1:]))
+}
+
+// createVectorSet returns the tree with element on given position replaced by target,
+// it copies only the path to this element
+func createVectorSet(node *vectorNode, position int, target Object) *vectorNode {
+	left := vectorSize(node.left)
+
+	if position < left {
+		return createVectorNode(node.value, createVectorSet(node.left, position, target), node.right)
+	} else if position > left {
+		return createVectorNode(node.value, node.left, createVectorSet(node.right, position-left-1, target))
+	}
+
+	return createVectorNode(target, node.left, node.right)
+}
+
+// balanceVectorTree returns a new node, rotated if heights of its branches differ by two
+func balanceVectorTree(value Object, left, right *vectorNode) *vectorNode {
+	leftHeight := vectorHeight(left)
+	rightHeight := vectorHeight(right)
+
+	if leftHeight > rightHeight+1 {
+		if vectorHeight(left.left) >= vectorHeight(left.right) {
+			return createVectorNode(left.value, left.left, createVectorNode(value, left.right, right))
+		}
+
+		pivot := left.right
+		return createVectorNode(pivot.value,
+			createVectorNode(left.value, left.left, pivot.left),
+			createVectorNode(value, pivot.right, right))
+	}
+
+	if rightHeight > leftHeight+1 {
+		if vectorHeight(right.right) >= vectorHeight(right.left) {
+			return createVectorNode(right.value, createVectorNode(value, left, right.left), right.right)
+		}
+
+		pivot := right.left
+		return createVectorNode(pivot.value,
+			createVectorNode(value, left, pivot.left),
+			createVectorNode(right.value, pivot.right, right.right))
+	}
+
+	return createVectorNode(value, left, right)
+}
+
+// joinVectorTree returns the balanced tree, which contains left tree, value and right tree in this order,
+// it takes O(|height(left) - height(right)|) operations
+func joinVectorTree(left *vectorNode, value Object, right *vectorNode) *vectorNode {
+	if vectorHeight(left) > vectorHeight(right)+1 {
+		return balanceVectorTree(left.value, left.left, joinVectorTree(left.right, value, right))
+	}
+	if vectorHeight(right) > vectorHeight(left)+1 {
+		return balanceVectorTree(right.value, joinVectorTree(left, value, right.left), right.right)
+	}
+
+	return createVectorNode(value, left, right)
+}
+
+// concatVectorTree returns the balanced tree, which contains both trees one after another
+func concatVectorTree(left, right *vectorNode) *vectorNode {
+	if right == nil {
+		return left
+	}
+	if left == nil {
+		return right
+	}
+
+	first, rest := splitVectorTree(right, 1)
+	return joinVectorTree(left, first.value, rest)
+}
+
+// splitVectorTree returns two balanced trees, the first one contains elements before given position,
+// and the second one contains the rest of them
+func splitVectorTree(node *vectorNode, position int) (*vectorNode, *vectorNode) {
+	if node == nil {
+		return nil, nil
+	}
+
+	left := vectorSize(node.left)
+
+	if position <= left {
+		leftPart, rightPart := splitVectorTree(node.left, position)
+		return leftPart, joinVectorTree(rightPart, node.value, node.right)
+	}
+
+	leftPart, rightPart := splitVectorTree(node.right, position-left-1)
+	return joinVectorTree(node.left, node.value, leftPart), rightPart
+}
+
+// eachVectorNode calls cb on each value of the tree in order
+func eachVectorNode(node *vectorNode, cb func(value Object)) {
+	for node != nil {
+		eachVectorNode(node.left, cb)
+		cb(node.value)
+		node = node.right
+	}
+}
+
+// iterateVectorNode calls yield on each element in order, returns false as soon as yield does
+func iterateVectorNode(node *vectorNode, yield func(value Object) bool) bool {
+	for node != nil {
+		if !iterateVectorNode(node.left, yield) || !yield(node.value) {
+			return false
+		}
+		node = node.right
+	}
+	return true
+}
+
+func vectorSize(node *vectorNode) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+func vectorHeight(node *vectorNode) int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
@@ -0,0 +1,203 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2016 Alexey Derbyshev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ugo_test
+
+import (
+	. "github.com/alxrm/ugo"
+	. "github.com/franela/goblin"
+	"math/rand"
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("#NewVector()", func() {
+		g.It("Should create Vector from the copy of Seq", func() {
+			inSeq := Seq{1, 2, 3, 4, 5}
+			vec := NewVector(inSeq)
+			inSeq[0] = 100
+
+			g.Assert(vec.Len()).Equal(5)
+			g.Assert(vec.Seq()).Equal(Seq{1, 2, 3, 4, 5})
+			g.Assert(NewVector(nil).Seq()).Equal(Seq{})
+			g.Assert(Vector{}.Len()).Equal(0)
+		})
+	})
+
+	g.Describe("#At()", func() {
+		g.It("Should return element on given position or nil", func() {
+			vec := NewVector(Seq{"a", "b", "c"})
+
+			g.Assert(vec.At(0)).Equal("a")
+			g.Assert(vec.At(2)).Equal("c")
+			g.Assert(vec.At(3)).Equal(nil)
+			g.Assert(vec.At(-1)).Equal(nil)
+			g.Assert(Vector{}.At(0)).Equal(nil)
+		})
+	})
+
+	g.Describe("#Set()", func() {
+		g.It("Should return changed Vector, keeping the original one", func() {
+			vec := NewVector(Seq{1, 2, 3})
+			changed := vec.Set(1, 20)
+
+			g.Assert(changed.Seq()).Equal(Seq{1, 20, 3})
+			g.Assert(vec.Seq()).Equal(Seq{1, 2, 3})
+			g.Assert(vec.Set(3, 0).Seq()).Equal(Seq{1, 2, 3})
+			g.Assert(vec.Set(-1, 0).Seq()).Equal(Seq{1, 2, 3})
+		})
+	})
+
+	g.Describe("#Append() and #Insert()", func() {
+		g.It("Should return extended Vector, keeping the original one", func() {
+			vec := NewVector(Seq{1, 2, 3})
+
+			g.Assert(vec.Append(4).Seq()).Equal(Seq{1, 2, 3, 4})
+			g.Assert(vec.Insert(0, 0).Seq()).Equal(Seq{0, 1, 2, 3})
+			g.Assert(vec.Insert(9, 2).Seq()).Equal(Seq{1, 2, 9, 3})
+			g.Assert(vec.Insert(9, -5).Seq()).Equal(Seq{9, 1, 2, 3})
+			g.Assert(vec.Insert(9, 50).Seq()).Equal(Seq{1, 2, 3, 9})
+			g.Assert(Vector{}.Append(1).Seq()).Equal(Seq{1})
+			g.Assert(vec.Seq()).Equal(Seq{1, 2, 3})
+		})
+	})
+
+	g.Describe("#Remove()", func() {
+		g.It("Should return Vector without element, keeping the original one", func() {
+			vec := NewVector(Seq{1, 2, 3})
+
+			g.Assert(vec.Remove(1).Seq()).Equal(Seq{1, 3})
+			g.Assert(vec.Remove(-1).Seq()).Equal(Seq{2, 3})
+			g.Assert(vec.Remove(10).Seq()).Equal(Seq{1, 2})
+			g.Assert(Vector{}.Remove(0).Seq()).Equal(Seq{})
+			g.Assert(vec.Seq()).Equal(Seq{1, 2, 3})
+		})
+	})
+
+	g.Describe("#Slice()", func() {
+		g.It("Should return part of Vector", func() {
+			vec := NewVector(Seq{0, 1, 2, 3, 4, 5})
+
+			g.Assert(vec.Slice(1, 4).Seq()).Equal(Seq{1, 2, 3})
+			g.Assert(vec.Slice(0, 100).Seq()).Equal(Seq{0, 1, 2, 3, 4, 5})
+			g.Assert(vec.Slice(4, 2).Seq()).Equal(Seq{})
+			g.Assert(vec.Slice(-3, 1).Seq()).Equal(Seq{0})
+			g.Assert(vec.Len()).Equal(6)
+		})
+	})
+
+	g.Describe("#Concat()", func() {
+		g.It("Should return Vector with elements of both Vectors", func() {
+			left := NewVector(Seq{1, 2})
+			right := NewVector(Seq{3, 4, 5})
+
+			g.Assert(left.Concat(right).Seq()).Equal(Seq{1, 2, 3, 4, 5})
+			g.Assert(left.Concat(Vector{}).Seq()).Equal(Seq{1, 2})
+			g.Assert(Vector{}.Concat(right).Seq()).Equal(Seq{3, 4, 5})
+			g.Assert(left.Seq()).Equal(Seq{1, 2})
+		})
+	})
+
+	g.Describe("#Each() and #Chain()", func() {
+		g.It("Should pass Vector elements to ugo functions", func() {
+			vec := NewVector(Seq{3, 1, 2})
+			result := NewSeq(0)
+			intComparator := func(l, r Object) int { return l.(int) - r.(int) }
+
+			vec.Each(func(cur, index, _ Object) { result = append(result, cur.(int)*10+index.(int)) })
+			vec.Each(nil)
+
+			g.Assert(result).Equal(Seq{30, 11, 22})
+			g.Assert(vec.Chain().SortBy(intComparator).Value()).Equal(Seq{1, 2, 3})
+			g.Assert(Max(vec.Seq(), intComparator)).Equal(3)
+			g.Assert(vec.Seq()).Equal(Seq{3, 1, 2})
+		})
+	})
+
+	g.Describe("#Iterator()", func() {
+		g.It("Should go through elements in order and stop early", func() {
+			vec := NewVector(Range(0, 100, 1))
+			taken := Seq{}
+
+			for current := range vec.Iterator() {
+				if current.(int) == 5 {
+					break
+				}
+				taken = append(taken, current)
+			}
+
+			g.Assert(taken).Equal(Seq{0, 1, 2, 3, 4})
+			g.Assert(From(vec.Iterator())).Equal(vec.Seq())
+			g.Assert(From(Vector{}.Iterator())).Equal(Seq{})
+		})
+	})
+
+	g.Describe("Random operations", func() {
+		g.It("Should behave like a slice and keep every version intact", func() {
+			rng := rand.New(rand.NewSource(42))
+			vec := Vector{}
+			model := Seq{}
+			versions := []Vector{}
+			models := []Seq{}
+
+			for i := 0; i < 2000; i++ {
+				position := 0
+				if len(model) != 0 {
+					position = rng.Intn(len(model))
+				}
+
+				switch rng.Intn(6) {
+				case 0, 1:
+					vec = vec.Append(i)
+					model = append(append(Seq{}, model...), i)
+				case 2:
+					vec = vec.Insert(i, position)
+					model = Insert(append(Seq{}, model...), i, position)
+				case 3:
+					vec = vec.Remove(position)
+					model = Remove(model, position)
+				case 4:
+					vec = vec.Set(position, -i)
+					if len(model) != 0 {
+						model = append(Seq{}, model...)
+						model[position] = -i
+					}
+				case 5:
+					half := NewVector(model[:len(model)/2])
+					vec = half.Concat(vec.Slice(len(model)/2, len(model)))
+				}
+
+				if i%100 == 0 {
+					versions = append(versions, vec)
+					models = append(models, model)
+				}
+			}
+
+			g.Assert(vec.Seq()).Equal(model)
+			for index, version := range versions {
+				g.Assert(version.Seq()).Equal(models[index])
+			}
+		})
+	})
+}